@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/samber/mo"
+	"github.com/urfave/cli/v2"
+)
+
+type Bookmark struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+type Bookmarks struct {
+	path      string
+	bookmarks []Bookmark
+}
+
+func NewBookmarks(path string) Bookmarks {
+	return Bookmarks{path: path}
+}
+
+func bookmarksPath() mo.Result[string] {
+	return configDir().Map(func(dir string) (string, error) {
+		return filepath.Join(dir, "bookmarks.json"), nil
+	})
+}
+
+func configDir() mo.Result[string] {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return mo.Ok(filepath.Join(dir, "arrow"))
+	}
+
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		return mo.Err[string](err)
+	}
+
+	return mo.Ok(filepath.Join(home, ".config", "arrow"))
+}
+
+func LoadBookmarks(path string) mo.Result[Bookmarks] {
+	bookmarks := NewBookmarks(path)
+	data, err := os.ReadFile(path)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return mo.Ok(bookmarks)
+	}
+
+	if err != nil {
+		return mo.Err[Bookmarks](err)
+	}
+
+	if err := json.Unmarshal(data, &bookmarks.bookmarks); err != nil {
+		return mo.Err[Bookmarks](err)
+	}
+
+	return mo.Ok(bookmarks)
+}
+
+func (b Bookmarks) List() []Bookmark {
+	return b.bookmarks
+}
+
+func (b Bookmarks) Get(name string) mo.Option[Bookmark] {
+	for _, bookmark := range b.bookmarks {
+		if bookmark.Name == name {
+			return mo.Some(bookmark)
+		}
+	}
+
+	return mo.None[Bookmark]()
+}
+
+// Add registers the bookmark, replacing any existing bookmark with the same name.
+func (b Bookmarks) Add(name, path string) Bookmarks {
+	bookmarks := []Bookmark{{Name: name, Path: path}}
+	for _, bookmark := range b.bookmarks {
+		if bookmark.Name != name {
+			bookmarks = append(bookmarks, bookmark)
+		}
+	}
+
+	sort.Slice(bookmarks, func(i, j int) bool {
+		return bookmarks[i].Name < bookmarks[j].Name
+	})
+	b.bookmarks = bookmarks
+
+	return b
+}
+
+func (b Bookmarks) Remove(name string) mo.Result[Bookmarks] {
+	if b.Get(name).IsAbsent() {
+		return mo.Errf[Bookmarks]("bookmark %q not found", name)
+	}
+
+	var bookmarks []Bookmark
+	for _, bookmark := range b.bookmarks {
+		if bookmark.Name != name {
+			bookmarks = append(bookmarks, bookmark)
+		}
+	}
+	b.bookmarks = bookmarks
+
+	return mo.Ok(b)
+}
+
+func (b Bookmarks) Save() error {
+	data, err := json.MarshalIndent(b.bookmarks, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return writeFile(b.path, data)
+}
+
+func (b Bookmarks) Directories() []Directory {
+	var directories []Directory
+	for _, bookmark := range b.bookmarks {
+		directories = append(directories, NewDirectory(bookmark.Path).WithLabel(bookmark.Name))
+	}
+
+	return directories
+}
+
+func (b Bookmark) String() string {
+	return fmt.Sprintf("%s\t%s", b.Name, b.Path)
+}
+
+func loadBookmarks() mo.Result[Bookmarks] {
+	path, err := bookmarksPath().Get()
+
+	if err != nil {
+		return mo.Err[Bookmarks](err)
+	}
+
+	return LoadBookmarks(path)
+}
+
+func bookmarkCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "bookmark",
+		Usage: "Manage bookmarked directories.",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "add",
+				Usage:     "Bookmark a directory, the working directory by default.",
+				ArgsUsage: "<name> [path]",
+				Action: func(ctx *cli.Context) error {
+					name := ctx.Args().Get(0)
+					if name == "" {
+						return errors.New("bookmark name is required")
+					}
+
+					path, err := filepath.Abs(ctx.Args().Get(1))
+					if err != nil {
+						return err
+					}
+
+					if info, err := os.Stat(path); err != nil {
+						return err
+					} else if !info.IsDir() {
+						return fmt.Errorf("%s is not a directory", path)
+					}
+
+					bookmarks, err := loadBookmarks().Get()
+					if err != nil {
+						return err
+					}
+
+					return bookmarks.Add(name, path).Save()
+				},
+			},
+			{
+				Name:      "rm",
+				Usage:     "Remove a bookmark.",
+				ArgsUsage: "<name>",
+				Action: func(ctx *cli.Context) error {
+					bookmarks, err := loadBookmarks().Get()
+					if err != nil {
+						return err
+					}
+
+					bookmarks, err = bookmarks.Remove(ctx.Args().Get(0)).Get()
+					if err != nil {
+						return err
+					}
+
+					return bookmarks.Save()
+				},
+			},
+			{
+				Name:  "ls",
+				Usage: "List bookmarks.",
+				Action: func(ctx *cli.Context) error {
+					bookmarks, err := loadBookmarks().Get()
+					if err != nil {
+						return err
+					}
+
+					for _, bookmark := range bookmarks.List() {
+						fmt.Println(bookmark.String())
+					}
+
+					return nil
+				},
+			},
+		},
+	}
+}
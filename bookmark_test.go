@@ -0,0 +1,88 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBookmarksAdd(t *testing.T) {
+	tests := []struct {
+		name      string
+		bookmarks []Bookmark
+		add       Bookmark
+		want      []Bookmark
+	}{
+		{
+			name:      "When adding a new bookmark",
+			bookmarks: []Bookmark{{Name: "foo", Path: "/foo"}},
+			add:       Bookmark{Name: "bar", Path: "/bar"},
+			want:      []Bookmark{{Name: "bar", Path: "/bar"}, {Name: "foo", Path: "/foo"}},
+		},
+		{
+			name:      "When replacing an existing bookmark",
+			bookmarks: []Bookmark{{Name: "foo", Path: "/foo"}},
+			add:       Bookmark{Name: "foo", Path: "/bar"},
+			want:      []Bookmark{{Name: "foo", Path: "/bar"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Bookmarks{bookmarks: tt.bookmarks}).Add(tt.add.Name, tt.add.Path).List(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bookmarks.Add() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookmarksRemove(t *testing.T) {
+	tests := []struct {
+		name      string
+		bookmarks []Bookmark
+		remove    string
+		want      []Bookmark
+		wantErr   bool
+	}{
+		{
+			name:      "When removing an existing bookmark",
+			bookmarks: []Bookmark{{Name: "bar", Path: "/bar"}, {Name: "foo", Path: "/foo"}},
+			remove:    "foo",
+			want:      []Bookmark{{Name: "bar", Path: "/bar"}},
+			wantErr:   false,
+		},
+		{
+			name:      "When removing an unknown bookmark",
+			bookmarks: []Bookmark{{Name: "foo", Path: "/foo"}},
+			remove:    "bar",
+			want:      nil,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := (Bookmarks{bookmarks: tt.bookmarks}).Remove(tt.remove)
+
+			if got.IsError() != tt.wantErr {
+				t.Errorf("bookmarks.Remove() error = %v, wantErr %v", got.Error(), tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got.OrEmpty().List(), tt.want) {
+				t.Errorf("bookmarks.Remove() = %v, want %v", got.OrEmpty().List(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadBookmarks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "arrow", "bookmarks.json")
+
+	if err := NewBookmarks(path).Add("foo", "/foo").Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := LoadBookmarks(path).OrEmpty().List()
+
+	if want := []Bookmark{{Name: "foo", Path: "/foo"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadBookmarks() = %v, want %v", got, want)
+	}
+}
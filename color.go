@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"regexp"
 	"strconv"
@@ -12,89 +13,93 @@ type Color string
 
 var rgbPattern = regexp.MustCompile("^#?([0-9a-fA-F]{6})$")
 
-var ForegroundColor = foreground()
-var HighlightColor = highlight()
-var CursorColor = cursor()
-var DisabledColor = disabled()
-var SymLinkColor = symLink()
-var CurrentDirectoryColor = currentDirectory()
-var PromptColor = prompt()
-var BorderColor = border()
-
-func (c Color) String() string {
-	return string(c)
+// Colors holds the colors of the UI as ANSI 256 colors or hex colors.
+type Colors struct {
+	Foreground       string `toml:"foreground"`
+	Highlight        string `toml:"highlight"`
+	Cursor           string `toml:"cursor"`
+	Disabled         string `toml:"disabled"`
+	SymLink          string `toml:"symlink"`
+	CurrentDirectory string `toml:"current_directory"`
+	Prompt           string `toml:"prompt"`
+	Border           string `toml:"border"`
 }
 
-func symLink() string {
-	e := os.Getenv("ARROW_SYMLINK_COLOR")
-	if e == "" {
-		return "36"
-	}
-
-	return ColorFromString(e).OrElse(Color("36")).String()
+type colorField struct {
+	name  string
+	env   string
+	value *string
 }
 
-func prompt() string {
-	e := os.Getenv("ARROW_PROMPT_COLOR")
-	if e == "" {
-		return "36"
-	}
-
-	return ColorFromString(e).OrElse(Color("36")).String()
+var DefaultColors = Colors{
+	Foreground:       "15",
+	Highlight:        "80",
+	Cursor:           "57",
+	Disabled:         "240",
+	SymLink:          "36",
+	CurrentDirectory: "57",
+	Prompt:           "36",
+	Border:           "80",
 }
 
-func currentDirectory() string {
-	e := os.Getenv("ARROW_CURRENT_DIRECTORY_COLOR")
-	if e == "" {
-		return "57"
-	}
+var colors = DefaultColors.Merge(EnvColors())
 
-	return ColorFromString(e).OrElse(Color("57")).String()
+func (c Color) String() string {
+	return string(c)
 }
 
-func disabled() string {
-	e := os.Getenv("ARROW_DISABLED_COLOR")
-	if e == "" {
-		return "240"
+func (c *Colors) fields() []colorField {
+	return []colorField{
+		{name: "foreground", env: "ARROW_FOREGROUND_COLOR", value: &c.Foreground},
+		{name: "highlight", env: "ARROW_HIGHLIGHT_COLOR", value: &c.Highlight},
+		{name: "cursor", env: "ARROW_CURSOR_COLOR", value: &c.Cursor},
+		{name: "disabled", env: "ARROW_DISABLED_COLOR", value: &c.Disabled},
+		{name: "symlink", env: "ARROW_SYMLINK_COLOR", value: &c.SymLink},
+		{name: "current_directory", env: "ARROW_CURRENT_DIRECTORY_COLOR", value: &c.CurrentDirectory},
+		{name: "prompt", env: "ARROW_PROMPT_COLOR", value: &c.Prompt},
+		{name: "border", env: "ARROW_BORDER_COLOR", value: &c.Border},
 	}
-
-	return ColorFromString(e).OrElse(Color("240")).String()
 }
 
-func cursor() string {
-	e := os.Getenv("ARROW_CURSOR_COLOR")
-	if e == "" {
-		return "57"
+// EnvColors reads the colors set by ARROW_*_COLOR environment variables.
+func EnvColors() Colors {
+	var c Colors
+	for _, field := range c.fields() {
+		*field.value = os.Getenv(field.env)
 	}
 
-	return ColorFromString(e).OrElse(Color("57")).String()
+	return c
 }
 
-func foreground() string {
-	e := os.Getenv("ARROW_FOREGROUND_COLOR")
-	if e == "" {
-		return "15"
+// Merge overrides c with the valid colors set in other.
+func (c Colors) Merge(other Colors) Colors {
+	fields := c.fields()
+	for i, field := range other.fields() {
+		ColorFromString(*field.value).ForEach(func(color Color) {
+			*fields[i].value = color.String()
+		})
 	}
 
-	return ColorFromString(e).OrElse(Color("15")).String()
+	return c
 }
 
-func highlight() string {
-	e := os.Getenv("ARROW_HIGHLIGHT_COLOR")
-	if e == "" {
-		return "80"
+func (c Colors) Validate() []error {
+	var errs []error
+	for _, field := range c.fields() {
+		if *field.value != "" && ColorFromString(*field.value).IsAbsent() {
+			errs = append(errs, fmt.Errorf("colors.%s: invalid color %q", field.name, *field.value))
+		}
 	}
 
-	return ColorFromString(e).OrElse(Color("80")).String()
+	return errs
 }
 
-func border() string {
-	e := os.Getenv("ARROW_BORDER_COLOR")
-	if e == "" {
-		return "80"
-	}
-
-	return ColorFromString(e).OrElse(Color("80")).String()
+// applyColors rebuilds every style from c.
+func applyColors(c Colors) {
+	colors = c
+	styles = newStyles(c)
+	dirPickerStyles = newDirPickerStyles(c)
+	previewStyles = newPreviewStyles(c)
 }
 
 func ColorFromString(s string) mo.Option[Color] {
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/samber/mo"
+	"github.com/urfave/cli/v2"
+)
+
+// Defaults are the default values of the command line options.
+type Defaults struct {
+	All           bool   `toml:"all"`
+	Icons         bool   `toml:"icons"`
+	Order         string `toml:"order"`
+	Reverse       bool   `toml:"reverse"`
+	Layout        string `toml:"layout"`
+	Height        string `toml:"height"`
+	Keymap        string `toml:"keymap"`
+	Theme         string `toml:"theme"`
+	RespectIgnore bool   `toml:"respect_ignore"`
+}
+
+// Projects are where the projects mode looks for projects.
+type Projects struct {
+	Roots []string `toml:"roots"`
+	// Depth is how many levels below the roots are searched, 0 for no limit.
+	Depth int `toml:"depth"`
+}
+
+type Config struct {
+	Defaults Defaults            `toml:"defaults"`
+	Projects Projects            `toml:"projects"`
+	Colors   Colors              `toml:"colors"`
+	Keys     map[string][]string `toml:"keys"`
+	Icons    map[string]string   `toml:"icons"`
+	// undecoded holds the keys in the file that do not correspond to any setting.
+	undecoded []string
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Defaults: Defaults{All: false, Icons: false, Order: ORDER_NAME.String(), Layout: LAYOUT_DEFAULT.String(), Keymap: PRESET_DEFAULT.String(), Theme: "default"},
+		Projects: Projects{Roots: []string{"~/src"}, Depth: 3},
+		Colors:   Colors{},
+		Keys:     map[string][]string{},
+		Icons:    map[string]string{},
+	}
+}
+
+func configPath() mo.Result[string] {
+	return configDir().Map(func(dir string) (string, error) {
+		return filepath.Join(dir, "config.toml"), nil
+	})
+}
+
+func LoadConfig(path string) mo.Result[Config] {
+	config := DefaultConfig()
+	data, err := os.ReadFile(path)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return mo.Ok(config)
+	}
+
+	if err != nil {
+		return mo.Err[Config](err)
+	}
+
+	meta, err := toml.Decode(string(data), &config)
+
+	if err != nil {
+		return mo.Err[Config](fmt.Errorf("%s: %w", path, err))
+	}
+
+	for _, key := range meta.Undecoded() {
+		config.undecoded = append(config.undecoded, key.String())
+	}
+
+	return mo.Ok(config)
+}
+
+func loadConfig() mo.Result[Config] {
+	path, err := configPath().Get()
+
+	if err != nil {
+		return mo.Err[Config](err)
+	}
+
+	return LoadConfig(path)
+}
+
+func (c Config) Validate() []error {
+	var errs []error
+
+	for _, key := range c.undecoded {
+		errs = append(errs, fmt.Errorf("%s: unknown setting", key))
+	}
+
+	if err := OrderFromString(c.Defaults.Order).Error(); err != nil {
+		errs = append(errs, fmt.Errorf("defaults.order: %w", err))
+	}
+
+	if err := LayoutFromString(c.Defaults.Layout).Error(); err != nil {
+		errs = append(errs, fmt.Errorf("defaults.layout: %w", err))
+	}
+
+	if err := HeightFromString(c.Defaults.Height).Error(); err != nil {
+		errs = append(errs, fmt.Errorf("defaults.height: %w", err))
+	}
+
+	if err := PresetFromString(c.Defaults.Keymap).Error(); err != nil {
+		errs = append(errs, fmt.Errorf("defaults.keymap: %w", err))
+	}
+
+	if err := ThemeFromString(c.Defaults.Theme).Error(); err != nil {
+		errs = append(errs, fmt.Errorf("defaults.theme: %w", err))
+	}
+
+	if c.Projects.Depth < 0 {
+		errs = append(errs, fmt.Errorf("projects.depth: must not be negative"))
+	}
+
+	errs = append(errs, c.Colors.Validate()...)
+	errs = append(errs, validateKeys(c.Keys)...)
+
+	return errs
+}
+
+// Effective applies the environment variables to the file settings, which take precedence over the defaults.
+// Invalid settings are replaced with their defaults.
+func (c Config) Effective() Config {
+	effective := DefaultConfig()
+	defaults := c.Defaults.WithEnv()
+	effective.Defaults.All = defaults.All
+	effective.Defaults.Icons = defaults.Icons
+	effective.Defaults.Reverse = defaults.Reverse
+	effective.Defaults.RespectIgnore = defaults.RespectIgnore
+
+	if OrderFromString(defaults.Order).IsOk() {
+		effective.Defaults.Order = defaults.Order
+	}
+
+	if LayoutFromString(defaults.Layout).IsOk() {
+		effective.Defaults.Layout = defaults.Layout
+	}
+
+	if HeightFromString(defaults.Height).IsOk() {
+		effective.Defaults.Height = defaults.Height
+	}
+
+	if PresetFromString(defaults.Keymap).IsOk() {
+		effective.Defaults.Keymap = defaults.Keymap
+	}
+
+	if ThemeFromString(defaults.Theme).IsOk() {
+		effective.Defaults.Theme = defaults.Theme
+	}
+
+	effective.Projects.Roots = c.Projects.Roots
+	if c.Projects.Depth >= 0 {
+		effective.Projects.Depth = c.Projects.Depth
+	}
+	// The background of the terminal is only known once the picker starts, so the dark variant stands in for it.
+	effective.Colors = c.ThemeColors(ThemeFromString(effective.Defaults.Theme).OrEmpty().Colors(true))
+	effective.Keys = PresetFromString(effective.Defaults.Keymap).OrEmpty().KeyMap().Override(c.Keys).Keys()
+
+	for name, icon := range icons {
+		effective.Icons[name] = icon
+	}
+
+	for name, icon := range c.Icons {
+		effective.Icons[strings.ToLower(name)] = icon
+	}
+
+	return effective
+}
+
+// ThemeColors overrides the colors of a theme with the ones set in the file and the environment.
+func (c Config) ThemeColors(theme Colors) Colors {
+	return theme.Merge(c.Colors).Merge(EnvColors())
+}
+
+// WithEnv overrides the defaults with the ARROW_* environment variables.
+func (d Defaults) WithEnv() Defaults {
+	if v, err := strconv.ParseBool(os.Getenv("ARROW_ALL")); err == nil {
+		d.All = v
+	}
+
+	if v, err := strconv.ParseBool(os.Getenv("ARROW_ICONS")); err == nil {
+		d.Icons = v
+	}
+
+	if v := os.Getenv("ARROW_ORDER"); v != "" {
+		d.Order = v
+	}
+
+	if v, err := strconv.ParseBool(os.Getenv("ARROW_REVERSE")); err == nil {
+		d.Reverse = v
+	}
+
+	if v, err := strconv.ParseBool(os.Getenv("ARROW_RESPECT_IGNORE")); err == nil {
+		d.RespectIgnore = v
+	}
+
+	if v := os.Getenv("ARROW_LAYOUT"); v != "" {
+		d.Layout = v
+	}
+
+	if v := os.Getenv("ARROW_HEIGHT"); v != "" {
+		d.Height = v
+	}
+
+	if v := os.Getenv("ARROW_KEYMAP"); v != "" {
+		d.Keymap = v
+	}
+
+	if v := os.Getenv("ARROW_THEME"); v != "" {
+		d.Theme = v
+	}
+
+	return d
+}
+
+// validateEnv reports the ARROW_* environment variables with invalid values.
+func validateEnv() []error {
+	var errs []error
+
+	for _, name := range []string{"ARROW_ALL", "ARROW_ICONS", "ARROW_REVERSE", "ARROW_RESPECT_IGNORE"} {
+		if v := os.Getenv(name); v != "" {
+			if _, err := strconv.ParseBool(v); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid boolean %q", name, v))
+			}
+		}
+	}
+
+	if v := os.Getenv("ARROW_ORDER"); v != "" {
+		if err := OrderFromString(v).Error(); err != nil {
+			errs = append(errs, fmt.Errorf("ARROW_ORDER: %w", err))
+		}
+	}
+
+	if v := os.Getenv("ARROW_LAYOUT"); v != "" {
+		if err := LayoutFromString(v).Error(); err != nil {
+			errs = append(errs, fmt.Errorf("ARROW_LAYOUT: %w", err))
+		}
+	}
+
+	if v := os.Getenv("ARROW_HEIGHT"); v != "" {
+		if err := HeightFromString(v).Error(); err != nil {
+			errs = append(errs, fmt.Errorf("ARROW_HEIGHT: %w", err))
+		}
+	}
+
+	if v := os.Getenv("ARROW_KEYMAP"); v != "" {
+		if err := PresetFromString(v).Error(); err != nil {
+			errs = append(errs, fmt.Errorf("ARROW_KEYMAP: %w", err))
+		}
+	}
+
+	if v := os.Getenv("ARROW_THEME"); v != "" {
+		if err := ThemeFromString(v).Error(); err != nil {
+			errs = append(errs, fmt.Errorf("ARROW_THEME: %w", err))
+		}
+	}
+
+	var c Colors
+	for _, field := range c.fields() {
+		if v := os.Getenv(field.env); v != "" && ColorFromString(v).IsAbsent() {
+			errs = append(errs, fmt.Errorf("%s: invalid color %q", field.env, v))
+		}
+	}
+
+	return errs
+}
+
+// apply makes the colors and icons of the effective config the ones used to render.
+func (c Config) apply() {
+	applyColors(c.Colors)
+	icons = c.Icons
+}
+
+func configCommand(config mo.Result[Config]) *cli.Command {
+	return &cli.Command{
+		Name:  "config",
+		Usage: "Inspect the configuration file.",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "validate",
+				Usage: "Report invalid settings in the configuration file.",
+				Action: func(ctx *cli.Context) error {
+					c, err := config.Get()
+					if err != nil {
+						return err
+					}
+
+					errs := append(c.Validate(), validateEnv()...)
+
+					for _, err := range errs {
+						fmt.Println(err)
+					}
+
+					if len(errs) > 0 {
+						return cli.Exit("", EXIT_ERROR)
+					}
+
+					configPath().ForEach(func(path string) {
+						fmt.Printf("%s is valid\n", path)
+					})
+					return nil
+				},
+			},
+			{
+				Name:  "dump",
+				Usage: "Print the effective configuration.",
+				Action: func(ctx *cli.Context) error {
+					c, err := config.Get()
+					if err != nil {
+						return err
+					}
+
+					return toml.NewEncoder(os.Stdout).Encode(c.Effective())
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name: "When valid config",
+			content: `
+[defaults]
+icons = true
+order = "time"
+
+[colors]
+foreground = "#009CD1"
+
+[keys]
+up = ["up", "ctrl+p"]
+`,
+			want: nil,
+		},
+		{
+			name: "When invalid config",
+			content: `
+[defaults]
+order = "color"
+keymap = "kakoune"
+theme = "monokai"
+unknown = true
+
+[projects]
+depth = -1
+
+[colors]
+foreground = "256"
+
+[keys]
+jump = ["ctrl+j"]
+`,
+			want: []string{
+				"defaults.unknown: unknown setting",
+				`defaults.order: unknown order "color"`,
+				`defaults.keymap: unknown keymap "kakoune"`,
+				`defaults.theme: unknown theme "monokai"`,
+				"projects.depth: must not be negative",
+				`colors.foreground: invalid color "256"`,
+				"keys.jump: unknown action",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.toml")
+
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := LoadConfig(path).Get()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, err := range config.Validate() {
+				got = append(got, err.Error())
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("config.Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffective(t *testing.T) {
+	t.Setenv("ARROW_ORDER", "time")
+	t.Setenv("ARROW_PROMPT_COLOR", "100")
+
+	config := DefaultConfig()
+	config.Defaults = Defaults{Icons: true, Order: "name", Layout: "rows", Keymap: "vim"}
+	config.Colors = Colors{Prompt: "200", Cursor: "201", Border: "invalid"}
+	config.Keys = map[string][]string{"up": {"ctrl+p"}}
+	got := config.Effective()
+
+	if want := (Defaults{All: false, Icons: true, Order: "time", Layout: "default", Keymap: "vim", Theme: "default"}); got.Defaults != want {
+		t.Errorf("config.Effective().Defaults = %v, want %v", got.Defaults, want)
+	}
+
+	if got.Colors.Prompt != "100" || got.Colors.Cursor != "201" || got.Colors.Border != DefaultColors.Border {
+		t.Errorf("config.Effective().Colors = %v", got.Colors)
+	}
+
+	if want := []string{"ctrl+p"}; !reflect.DeepEqual(got.Keys["up"], want) {
+		t.Errorf("config.Effective().Keys[up] = %v, want %v", got.Keys["up"], want)
+	}
+
+	if want := []string{"down", "j"}; !reflect.DeepEqual(got.Keys["down"], want) {
+		t.Errorf("config.Effective().Keys[down] = %v, want %v", got.Keys["down"], want)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -12,8 +13,11 @@ import (
 )
 
 type Directory struct {
-	path string
-	fsys fs.FS
+	path  string
+	fsys  fs.FS
+	label string
+	// project is the marker file that made the directory be found as a project, such as go.mod.
+	project string
 }
 
 type Order int
@@ -21,8 +25,46 @@ type Order int
 const (
 	ORDER_NAME Order = iota
 	ORDER_TIME
+	ORDER_NATURAL
+	ORDER_NOCASE
+	ORDER_ENTRIES
+	ORDER_SIZE
+	ORDER_FRECENCY
 )
 
+var orders = map[string]Order{
+	"name":     ORDER_NAME,
+	"time":     ORDER_TIME,
+	"natural":  ORDER_NATURAL,
+	"nocase":   ORDER_NOCASE,
+	"entries":  ORDER_ENTRIES,
+	"size":     ORDER_SIZE,
+	"frecency": ORDER_FRECENCY,
+}
+
+func OrderFromString(s string) mo.Result[Order] {
+	if order, ok := orders[s]; ok {
+		return mo.Ok(order)
+	}
+
+	return mo.Errf[Order]("unknown order %q", s)
+}
+
+func (o Order) String() string {
+	for name, order := range orders {
+		if order == o {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// Next returns the order that follows o when cycling through the orders.
+func (o Order) Next() Order {
+	return (o + 1) % Order(len(orders))
+}
+
 func NewDirectory(path string) Directory {
 	return Directory{path: path, fsys: os.DirFS(path)}
 }
@@ -35,6 +77,25 @@ func (d Directory) Name() string {
 	return filepath.Base(d.String())
 }
 
+// Label returns the text displayed in the picker, which defaults to the directory name.
+func (d Directory) Label() string {
+	if d.label == "" {
+		return d.Name()
+	}
+
+	return d.label
+}
+
+func (d Directory) WithLabel(label string) Directory {
+	d.label = label
+	return d
+}
+
+func (d Directory) WithProject(marker string) Directory {
+	d.project = marker
+	return d
+}
+
 func (d Directory) IsHidden() bool {
 	return strings.HasPrefix(d.Name(), ".")
 }
@@ -66,6 +127,7 @@ func (d Directory) Dirs(showAll bool, order Order) mo.Result[[]Directory] {
 		}
 	}
 
+	// ORDER_SIZE and ORDER_FRECENCY need more than the listing, so they are left in name order for the picker to sort.
 	switch order {
 	case ORDER_NAME:
 	case ORDER_TIME:
@@ -74,6 +136,24 @@ func (d Directory) Dirs(showAll bool, order Order) mo.Result[[]Directory] {
 			bf, _ := entries[j].Info()
 			return bf.ModTime().After(af.ModTime())
 		})
+	case ORDER_NATURAL:
+		sort.SliceStable(entries, func(i, j int) bool {
+			return naturalLess(entries[i].Name(), entries[j].Name())
+		})
+	case ORDER_NOCASE:
+		sort.SliceStable(entries, func(i, j int) bool {
+			return strings.ToLower(entries[i].Name()) < strings.ToLower(entries[j].Name())
+		})
+	case ORDER_ENTRIES:
+		counts := map[string]int{}
+		for _, entry := range entries {
+			children, _ := fs.ReadDir(d.fsys, entry.Name())
+			counts[entry.Name()] = len(children)
+		}
+
+		sort.SliceStable(entries, func(i, j int) bool {
+			return counts[entries[i].Name()] > counts[entries[j].Name()]
+		})
 	}
 
 	var directories []Directory
@@ -84,6 +164,35 @@ func (d Directory) Dirs(showAll bool, order Order) mo.Result[[]Directory] {
 	return mo.Ok(directories)
 }
 
+// Entries lists every file and directory, directories first.
+func (d Directory) Entries(showAll bool) mo.Result[[]fs.FileInfo] {
+	files, err := fs.ReadDir(d.fsys, ".")
+
+	if err != nil {
+		return mo.Err[[]fs.FileInfo](err)
+	}
+
+	var entries []fs.FileInfo
+	for _, file := range files {
+		if !showAll && strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, info)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].IsDir() && !entries[j].IsDir()
+	})
+
+	return mo.Ok(entries)
+}
+
 func (d Directory) SymLink() mo.Option[string] {
 	return getSymlink(d.String())
 }
@@ -117,3 +226,54 @@ func getSymlink(path string) mo.Option[string] {
 		return mo.None[string]()
 	}
 }
+
+// naturalLess compares the numbers in a and b by their value, so that v2 comes before v10.
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		ca, cb := chunk(a), chunk(b)
+
+		if isDigit(ca[0]) && isDigit(cb[0]) {
+			na, nb := strings.TrimLeft(ca, "0"), strings.TrimLeft(cb, "0")
+
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+
+			if na != nb {
+				return na < nb
+			}
+		}
+
+		if ca != cb {
+			return ca < cb
+		}
+
+		a, b = a[len(ca):], b[len(cb):]
+	}
+
+	return len(a) < len(b)
+}
+
+// chunk returns the leading run of digits or non-digits of s.
+func chunk(s string) string {
+	digit := isDigit(s[0])
+	for i := 1; i < len(s); i++ {
+		if isDigit(s[i]) != digit {
+			return s[:i]
+		}
+	}
+
+	return s
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// Reversed returns a copy of directories in the opposite order.
+func Reversed(directories []Directory) []Directory {
+	reversed := slices.Clone(directories)
+	slices.Reverse(reversed)
+
+	return reversed
+}
@@ -119,3 +119,121 @@ func TestDirs(t *testing.T) {
 		})
 	}
 }
+
+func TestEntries(t *testing.T) {
+	fs := fstest.MapFS{
+		"bar":      {Mode: fs.ModeDir},
+		"foo.txt":  {Data: []byte("foo")},
+		".hidden":  {Data: []byte("")},
+		"baz/file": {Data: []byte("")},
+	}
+	tests := []struct {
+		name    string
+		showAll bool
+		want    []string
+	}{
+		{
+			name:    "When listing entries",
+			showAll: false,
+			want:    []string{"bar", "baz", "foo.txt"},
+		},
+		{
+			name:    "When display all entries",
+			showAll: true,
+			want:    []string{"bar", "baz", ".hidden", "foo.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, entry := range (Directory{path: ".", fsys: fs}).Entries(tt.showAll).OrElse(nil) {
+				got = append(got, entry.Name())
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("directory.Entries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDirsOrder(t *testing.T) {
+	fs := fstest.MapFS{
+		"v10":    {Mode: fs.ModeDir},
+		"v2/a":   {Data: []byte("")},
+		"v2/b":   {Data: []byte("")},
+		"Beta/a": {Data: []byte("")},
+		"alpha":  {Mode: fs.ModeDir},
+	}
+	tests := []struct {
+		name  string
+		order Order
+		want  []string
+	}{
+		{
+			name:  "When sorting naturally",
+			order: ORDER_NATURAL,
+			want:  []string{"Beta", "alpha", "v2", "v10"},
+		},
+		{
+			name:  "When sorting ignoring case",
+			order: ORDER_NOCASE,
+			want:  []string{"alpha", "Beta", "v10", "v2"},
+		},
+		{
+			name:  "When sorting by number of entries",
+			order: ORDER_ENTRIES,
+			want:  []string{"v2", "Beta", "alpha", "v10"},
+		},
+		{
+			name:  "When sorting by size",
+			order: ORDER_SIZE,
+			want:  []string{"Beta", "alpha", "v10", "v2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range (Directory{path: ".", fsys: fs}).Dirs(false, tt.order).OrElse([]Directory{}) {
+				got = append(got, d.String())
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("directory.Dirs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNaturalLess(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want bool
+	}{
+		{a: "v2", b: "v10", want: true},
+		{a: "v10", b: "v2", want: false},
+		{a: "v1.9", b: "v1.10", want: true},
+		{a: "a", b: "b", want: true},
+		{a: "a", b: "a1", want: true},
+		{a: "a01", b: "a1", want: true},
+		{a: "a1", b: "a1", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			if got := naturalLess(tt.a, tt.b); got != tt.want {
+				t.Errorf("naturalLess(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderNext(t *testing.T) {
+	if got := ORDER_NAME.Next(); got != ORDER_TIME {
+		t.Errorf("ORDER_NAME.Next() = %v, want %v", got, ORDER_TIME)
+	}
+
+	if got := ORDER_FRECENCY.Next(); got != ORDER_NAME {
+		t.Errorf("ORDER_FRECENCY.Next() = %v, want %v", got, ORDER_NAME)
+	}
+}
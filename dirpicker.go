@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	zone "github.com/lrstanley/bubblezone"
@@ -13,20 +14,44 @@ type DirPickerStyles struct {
 	Symlink        lipgloss.Style
 	Text           lipgloss.Style
 	Selected       lipgloss.Style
+	Matched        lipgloss.Style
+	Git            lipgloss.Style
+	Marked         lipgloss.Style
+	Dirty          lipgloss.Style
 	Error          lipgloss.Style
 	EmptyDirectory lipgloss.Style
 }
 
-var dirPickerStyles = DirPickerStyles{
-	Cursor:         lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(CursorColor)),
-	Symlink:        lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(SymLinkColor)),
-	Text:           lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(ForegroundColor)),
-	Selected:       lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(HighlightColor)).Bold(true),
-	Error:          lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color("9")).PaddingLeft(2),
-	EmptyDirectory: lipgloss.DefaultRenderer().NewStyle().Background(lipgloss.Color(DisabledColor)).MarginLeft(2).SetString(" No directory found."),
+var dirPickerStyles = newDirPickerStyles(colors)
+
+func newDirPickerStyles(c Colors) DirPickerStyles {
+	return DirPickerStyles{
+		Cursor:         lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(c.Cursor)),
+		Symlink:        lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(c.SymLink)),
+		Text:           lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(c.Foreground)),
+		Selected:       lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(c.Highlight)).Bold(true),
+		Matched:        lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(c.Highlight)).Underline(true),
+		Git:            lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(c.Disabled)),
+		Marked:         lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(c.Cursor)),
+		Dirty:          lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(c.Cursor)),
+		Error:          lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color("9")).PaddingLeft(2),
+		EmptyDirectory: lipgloss.DefaultRenderer().NewStyle().Background(lipgloss.Color(c.Disabled)).MarginLeft(2).SetString(" No directory found."),
+	}
+}
+
+// visibleWindow returns the range of the directories shown in height lines, scrolled to keep the selected one in view.
+func visibleWindow(count, selectedIndex, height int) (int, int) {
+	if height <= 0 {
+		return 0, 0
+	}
+
+	start := max(selectedIndex-height+1, 0)
+	end := min(start+height, count)
+
+	return min(start, end), end
 }
 
-func DirPickerView(directories []Directory, selectedIndex, height int, displayIcons bool, hasChildDirectory mo.Option[bool], err error) string {
+func DirPickerView(directories []Directory, selectedIndex, height int, displayIcons bool, hasChildDirectory mo.Option[bool], err error, matcher Matcher, statuses map[string]mo.Option[GitStatus], marked map[string]bool) string {
 	if err != nil {
 		return dirPickerStyles.Error.Render(err.Error())
 	}
@@ -34,32 +59,23 @@ func DirPickerView(directories []Directory, selectedIndex, height int, displayIc
 	if len(directories) == 0 {
 		return dirPickerStyles.EmptyDirectory.String()
 	}
-	var displayDirectories []Directory
-	var displayStart int
 
 	if height == 0 {
 		return ""
 	}
 
-	if selectedIndex+1 > height {
-		if len(directories) > selectedIndex {
-			displayStart, displayDirectories = selectedIndex-height+1, directories[selectedIndex-height+1:selectedIndex+1]
-		} else {
-			displayStart, displayDirectories = selectedIndex-height+1, directories[selectedIndex-height+1:]
-		}
-	} else if len(directories) > height {
-		displayStart, displayDirectories = 0, directories[0:height]
-	} else {
-		displayStart, displayDirectories = 0, directories
-	}
+	displayStart, displayEnd := visibleWindow(len(directories), selectedIndex, height)
+	displayDirectories := directories[displayStart:displayEnd]
 
 	var lines []string
 
 	for i, directory := range displayDirectories {
 		selected := selectedIndex == i+displayStart
-		name := directory.Name()
 		icon := GetIcon(directory, selected, displayIcons)
+		mark := markView(marked, directory)
 		msg := ""
+		positions := matcher.Match(directory).OrEmpty().Positions
+		badge := gitBadge(statuses[directory.String()])
 
 		if !hasChildDirectory.OrElse(true) {
 			msg = dirPickerStyles.EmptyDirectory.String()
@@ -67,18 +83,19 @@ func DirPickerView(directories []Directory, selectedIndex, height int, displayIc
 
 		line := directory.SymLink().Match(
 			func(symLink string) (string, bool) {
-				s := fmt.Sprintf("%s%s → %s", icon, name, symLink)
 				if selected {
-					return fmt.Sprintf("%s %s%s", dirPickerStyles.Cursor.Render("❯"), dirPickerStyles.Selected.Render(s), msg), true
+					s := dirPickerStyles.Selected.Render(icon) + highlight(directory.Label(), positions, dirPickerStyles.Selected) + dirPickerStyles.Selected.Render(" → "+symLink) + badge
+					return fmt.Sprintf("%s %s%s%s", dirPickerStyles.Cursor.Render("❯"), mark, s, msg), true
 				} else {
-					return fmt.Sprintf("  %s", dirPickerStyles.Text.Render(s)), true
+					s := dirPickerStyles.Text.Render(icon) + highlight(directory.Label(), positions, dirPickerStyles.Text) + dirPickerStyles.Text.Render(" → "+symLink) + badge
+					return fmt.Sprintf("  %s%s", mark, s), true
 				}
 			},
 			func() (string, bool) {
 				if selected {
-					return fmt.Sprintf("%s %s%s%s", dirPickerStyles.Cursor.Render("❯"), dirPickerStyles.Selected.Render(icon), dirPickerStyles.Selected.Render(name), msg), true
+					return fmt.Sprintf("%s %s%s%s%s%s", dirPickerStyles.Cursor.Render("❯"), mark, dirPickerStyles.Selected.Render(icon), highlight(directory.Label(), positions, dirPickerStyles.Selected), badge, msg), true
 				} else {
-					return fmt.Sprintf("  %s%s", icon, dirPickerStyles.Text.Render(name)), true
+					return fmt.Sprintf("  %s%s%s%s", mark, icon, highlight(directory.Label(), positions, dirPickerStyles.Text), badge), true
 				}
 			},
 		).OrElse("")
@@ -91,3 +108,63 @@ func DirPickerView(directories []Directory, selectedIndex, height int, displayIc
 		lines...,
 	)
 }
+
+// markView is the column of the markers of the directories selected in multi-select mode, which is left out otherwise.
+func markView(marked map[string]bool, d Directory) string {
+	if marked == nil {
+		return ""
+	}
+
+	if marked[d.String()] {
+		return dirPickerStyles.Marked.Render("✓ ")
+	}
+
+	return "  "
+}
+
+// gitBadge shows the branch of a repository, how far it is ahead of and behind its upstream, and whether it has changes.
+func gitBadge(status mo.Option[GitStatus]) string {
+	s, ok := status.Get()
+	if !ok {
+		return ""
+	}
+
+	badge := dirPickerStyles.Git.Render(" " + s.String())
+	if s.Dirty {
+		badge += dirPickerStyles.Dirty.Render(" *")
+	}
+
+	return badge
+}
+
+// highlight renders the runes of s at positions in the matched style and the others in style.
+func highlight(s string, positions []int, style lipgloss.Style) string {
+	if len(positions) == 0 {
+		return style.Render(s)
+	}
+
+	matched := dirPickerStyles.Matched.Inherit(style)
+	isMatched := map[int]bool{}
+	for _, p := range positions {
+		isMatched[p] = true
+	}
+
+	var b strings.Builder
+	runes := []rune(s)
+	start := 0
+
+	for i := 1; i <= len(runes); i++ {
+		if i < len(runes) && isMatched[i] == isMatched[start] {
+			continue
+		}
+
+		if isMatched[start] {
+			b.WriteString(matched.Render(string(runes[start:i])))
+		} else {
+			b.WriteString(style.Render(string(runes[start:i])))
+		}
+		start = i
+	}
+
+	return b.String()
+}
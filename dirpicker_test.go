@@ -28,6 +28,8 @@ func TestDirPickerView(t *testing.T) {
 		displayIcons      bool
 		hasChildDirectory mo.Option[bool]
 		err               error
+		statuses          map[string]mo.Option[GitStatus]
+		marked            map[string]bool
 		want              string
 	}{
 		{
@@ -73,6 +75,30 @@ func TestDirPickerView(t *testing.T) {
 			want: lipgloss.JoinVertical(
 				lipgloss.Top, zone.Mark("foo", "  foo"), zone.Mark("foo/bar", "❯ bar")),
 		},
+		{
+			name:              "When a directory is a git repository",
+			directories:       []Directory{{path: "foo", fsys: fs}, {path: "foo/bar", fsys: fs}},
+			selectedIndex:     0,
+			height:            100,
+			displayIcons:      false,
+			hasChildDirectory: mo.None[bool](),
+			err:               nil,
+			statuses:          map[string]mo.Option[GitStatus]{"foo/bar": mo.Some(GitStatus{Branch: "main", Ahead: 1, Dirty: true})},
+			want: lipgloss.JoinVertical(
+				lipgloss.Top, zone.Mark("foo", "❯ foo"), zone.Mark("foo/bar", "  bar main ↑1 *")),
+		},
+		{
+			name:              "When a directory is marked in multi-select mode",
+			directories:       []Directory{{path: "foo", fsys: fs}, {path: "foo/bar", fsys: fs}},
+			selectedIndex:     0,
+			height:            100,
+			displayIcons:      false,
+			hasChildDirectory: mo.None[bool](),
+			err:               nil,
+			marked:            map[string]bool{"foo/bar": true},
+			want: lipgloss.JoinVertical(
+				lipgloss.Top, zone.Mark("foo", "❯   foo"), zone.Mark("foo/bar", "  ✓ bar")),
+		},
 		{
 			name:              "When has error",
 			directories:       []Directory{{path: "foo", fsys: fs}, {path: "foo/bar", fsys: fs}},
@@ -87,10 +113,62 @@ func TestDirPickerView(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := DirPickerView(tt.directories, tt.selectedIndex, tt.height, tt.displayIcons, tt.hasChildDirectory, tt.err); got != tt.want {
+			if got := DirPickerView(tt.directories, tt.selectedIndex, tt.height, tt.displayIcons, tt.hasChildDirectory, tt.err, Matcher{}, tt.statuses, tt.marked); got != tt.want {
 				fmt.Println(got)
 				t.Errorf("DirPickerView = %v, want = %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestVisibleWindow(t *testing.T) {
+	tests := []struct {
+		name          string
+		count         int
+		selectedIndex int
+		height        int
+		wantStart     int
+		wantEnd       int
+	}{
+		{
+			name:          "When every directory fits",
+			count:         3,
+			selectedIndex: 2,
+			height:        5,
+			wantStart:     0,
+			wantEnd:       3,
+		},
+		{
+			name:          "When the selected directory is below the first lines",
+			count:         10,
+			selectedIndex: 6,
+			height:        4,
+			wantStart:     3,
+			wantEnd:       7,
+		},
+		{
+			name:          "When there is no directory",
+			count:         0,
+			selectedIndex: 0,
+			height:        4,
+			wantStart:     0,
+			wantEnd:       0,
+		},
+		{
+			name:          "When the size of the terminal is not known yet",
+			count:         10,
+			selectedIndex: 0,
+			height:        0,
+			wantStart:     0,
+			wantEnd:       0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if start, end := visibleWindow(tt.count, tt.selectedIndex, tt.height); start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("visibleWindow() = %v, %v, want %v, %v", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
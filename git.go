@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/samber/mo"
+)
+
+// gitSemaphore bounds the number of repositories inspected at once.
+var gitSemaphore = make(chan struct{}, runtime.NumCPU())
+
+// gitTimeout bounds how long git may take to report on a large repository.
+const gitTimeout = 2 * time.Second
+
+// GitStatus is the state of the working tree of a repository.
+type GitStatus struct {
+	Branch string `json:"branch"`
+	Ahead  int    `json:"ahead"`
+	Behind int    `json:"behind"`
+	Dirty  bool   `json:"dirty"`
+}
+
+type gitMsg struct {
+	path   string
+	status mo.Option[GitStatus]
+}
+
+func loadGitStatus(d Directory) tea.Cmd {
+	return func() tea.Msg {
+		gitSemaphore <- struct{}{}
+		defer func() { <-gitSemaphore }()
+
+		return gitMsg{path: d.String(), status: ReadGitStatus(d)}
+	}
+}
+
+// ReadGitStatus reads the branch from the metadata of the repository or worktree at d.
+// The counts of commits ahead and behind and the dirty marker come from git, and are left out if it cannot be run in time.
+// Untracked files are not looked for, and git takes no locks, so that it never gets in the way of git run by the user.
+func ReadGitStatus(d Directory) mo.Option[GitStatus] {
+	head, ok := readHead(d).Get()
+	if !ok {
+		return mo.None[GitStatus]()
+	}
+
+	status := GitStatus{Branch: branchFromHead(head)}
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "--no-optional-locks", "-C", d.String(), "status", "--porcelain=v2", "--branch", "--untracked-files=no")
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0")
+	out, err := cmd.Output()
+
+	if err == nil {
+		status = parseGitStatus(string(out), status)
+	}
+
+	return mo.Some(status)
+}
+
+// readHead reads HEAD from the .git directory of d, or from the git directory a .git file of a worktree points to.
+func readHead(d Directory) mo.Option[string] {
+	info, err := fs.Stat(d.fsys, ".git")
+	if err != nil {
+		return mo.None[string]()
+	}
+
+	if info.IsDir() {
+		head, err := fs.ReadFile(d.fsys, ".git/HEAD")
+		if err != nil {
+			return mo.None[string]()
+		}
+
+		return mo.Some(strings.TrimSpace(string(head)))
+	}
+
+	data, err := fs.ReadFile(d.fsys, ".git")
+	if err != nil {
+		return mo.None[string]()
+	}
+
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return mo.None[string]()
+	}
+
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(d.String(), gitDir)
+	}
+
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return mo.None[string]()
+	}
+
+	return mo.Some(strings.TrimSpace(string(head)))
+}
+
+// branchFromHead returns the branch HEAD refers to, or the abbreviated commit when it is detached.
+func branchFromHead(head string) string {
+	if ref, ok := strings.CutPrefix(head, "ref: "); ok {
+		return strings.TrimPrefix(ref, "refs/heads/")
+	}
+
+	if len(head) > 7 {
+		return head[:7]
+	}
+
+	return head
+}
+
+// parseGitStatus reads the output of git status --porcelain=v2 --branch.
+func parseGitStatus(out string, status GitStatus) GitStatus {
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "# branch.ab "):
+			fmt.Sscanf(strings.TrimPrefix(line, "# branch.ab "), "+%d -%d", &status.Ahead, &status.Behind)
+		case strings.HasPrefix(line, "#"):
+		default:
+			status.Dirty = true
+		}
+	}
+
+	return status
+}
+
+func (s GitStatus) String() string {
+	var b strings.Builder
+	b.WriteString(s.Branch)
+
+	if s.Ahead > 0 {
+		fmt.Fprintf(&b, " ↑%d", s.Ahead)
+	}
+
+	if s.Behind > 0 {
+		fmt.Fprintf(&b, " ↓%d", s.Behind)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/samber/mo"
+)
+
+func TestReadHead(t *testing.T) {
+	worktree := t.TempDir()
+	if err := os.WriteFile(filepath.Join(worktree, "HEAD"), []byte("ref: refs/heads/feature\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want mo.Option[string]
+	}{
+		{
+			name: "When the directory is a repository",
+			fsys: fstest.MapFS{
+				".git":      {Mode: fs.ModeDir},
+				".git/HEAD": {Data: []byte("ref: refs/heads/main\n")},
+			},
+			want: mo.Some("ref: refs/heads/main"),
+		},
+		{
+			name: "When the directory is a worktree",
+			fsys: fstest.MapFS{
+				".git": {Data: []byte("gitdir: " + worktree + "\n")},
+			},
+			want: mo.Some("ref: refs/heads/feature"),
+		},
+		{
+			name: "When the directory is not a repository",
+			fsys: fstest.MapFS{
+				"src": {Mode: fs.ModeDir},
+			},
+			want: mo.None[string](),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readHead(Directory{path: "/repo", fsys: tt.fsys}); got != tt.want {
+				t.Errorf("readHead() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBranchFromHead(t *testing.T) {
+	tests := []struct {
+		name string
+		head string
+		want string
+	}{
+		{name: "When HEAD refers to a branch", head: "ref: refs/heads/feature/login", want: "feature/login"},
+		{name: "When HEAD is detached", head: "3b18e512dba79e4c8300dd08aeb37f8e728b8dad", want: "3b18e51"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := branchFromHead(tt.head); got != tt.want {
+				t.Errorf("branchFromHead() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGitStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want GitStatus
+	}{
+		{
+			name: "When the working tree is clean",
+			out:  "# branch.oid 3b18e51\n# branch.head main\n# branch.upstream origin/main\n# branch.ab +0 -0\n",
+			want: GitStatus{Branch: "main"},
+		},
+		{
+			name: "When the branch is ahead and behind with changes",
+			out:  "# branch.oid 3b18e51\n# branch.head main\n# branch.upstream origin/main\n# branch.ab +2 -3\n1 .M N... 100644 100644 100644 3b18e51 3b18e51 main.go\n",
+			want: GitStatus{Branch: "main", Ahead: 2, Behind: 3, Dirty: true},
+		},
+		{
+			name: "When there are only untracked files",
+			out:  "# branch.oid 3b18e51\n# branch.head main\n? notes.txt\n",
+			want: GitStatus{Branch: "main", Dirty: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseGitStatus(tt.out, GitStatus{Branch: "main"}); got != tt.want {
+				t.Errorf("parseGitStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
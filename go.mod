@@ -3,15 +3,17 @@ module github.com/harehare/arrow
 go 1.24.0
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/charmbracelet/bubbles v0.21.0
 	github.com/charmbracelet/bubbletea v1.3.10
 	github.com/charmbracelet/lipgloss v1.1.0
-	github.com/lithammer/fuzzysearch v1.1.8
+	github.com/fsnotify/fsnotify v1.9.0
 	github.com/lrstanley/bubblezone v0.0.0-20230911164824-e3824f1adde9
 	github.com/muesli/termenv v0.16.0
 	github.com/samber/mo v1.15.0
 	github.com/urfave/cli/v2 v2.27.7
 	golang.org/x/exp v0.0.0-20231006140011-7918f672742d
+	golang.org/x/text v0.9.0
 )
 
 require (
@@ -34,5 +36,4 @@ require (
 	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
 	github.com/xrash/smetrics v0.0.0-20240521201337-686a1a2994c1 // indirect
 	golang.org/x/sys v0.36.0 // indirect
-	golang.org/x/text v0.9.0 // indirect
 )
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/samber/mo"
+)
+
+type Visit struct {
+	Path      string    `json:"path"`
+	Count     int       `json:"count"`
+	LastVisit time.Time `json:"last_visit"`
+}
+
+type History struct {
+	path   string
+	visits map[string]Visit
+	// added are the visits made since the history was loaded, which are merged into the file when it is saved.
+	added []Visit
+}
+
+func NewHistory(path string) History {
+	return History{path: path, visits: map[string]Visit{}}
+}
+
+func historyPath() mo.Result[string] {
+	return dataDir().Map(func(dir string) (string, error) {
+		return filepath.Join(dir, "history.json"), nil
+	})
+}
+
+func dataDir() mo.Result[string] {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return mo.Ok(filepath.Join(dir, "arrow"))
+	}
+
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		return mo.Err[string](err)
+	}
+
+	return mo.Ok(filepath.Join(home, ".local", "share", "arrow"))
+}
+
+func LoadHistory(path string) mo.Result[History] {
+	history := NewHistory(path)
+	data, err := os.ReadFile(path)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return mo.Ok(history)
+	}
+
+	if err != nil {
+		return mo.Err[History](err)
+	}
+
+	var visits []Visit
+	if err := json.Unmarshal(data, &visits); err != nil {
+		return mo.Err[History](err)
+	}
+
+	for _, visit := range visits {
+		history.visits[visit.Path] = visit
+	}
+
+	return mo.Ok(history)
+}
+
+func (h History) Add(path string, now time.Time) History {
+	visit := h.visits[path]
+	visit.Path = path
+	visit.Count++
+	visit.LastVisit = now
+	h.visits[path] = visit
+	h.added = append(slices.Clone(h.added), Visit{Path: path, LastVisit: now})
+
+	return h
+}
+
+// Save writes the visits added since the history was loaded into the file as it is now, so that those of
+// another arrow saved in the meantime are kept.
+func (h History) Save() error {
+	if h.path == "" {
+		return nil
+	}
+
+	saved, err := LoadHistory(h.path).Get()
+	if err != nil {
+		return err
+	}
+
+	for _, visit := range h.added {
+		saved = saved.Add(visit.Path, visit.LastVisit)
+	}
+
+	data, err := json.Marshal(saved.Ranked(time.Now()))
+
+	if err != nil {
+		return err
+	}
+
+	return writeFile(h.path, data)
+}
+
+// writeFile replaces the file at path with data by renaming a temporary file over it, so that a crash or another
+// arrow saving at the same time never leaves it half written.
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// Ranked returns visits ordered by frecency, the most relevant first.
+func (h History) Ranked(now time.Time) []Visit {
+	var visits []Visit
+	for _, visit := range h.visits {
+		visits = append(visits, visit)
+	}
+
+	sort.SliceStable(visits, func(i, j int) bool {
+		si, sj := visits[i].Frecency(now), visits[j].Frecency(now)
+		if si == sj {
+			return visits[i].Path < visits[j].Path
+		}
+		return si > sj
+	})
+
+	return visits
+}
+
+// Directories returns the visited directories that still exist, ordered by frecency.
+func (h History) Directories(now time.Time) []Directory {
+	var directories []Directory
+	for _, visit := range h.Ranked(now) {
+		if info, err := os.Stat(visit.Path); err == nil && info.IsDir() {
+			directories = append(directories, NewDirectory(visit.Path).WithLabel(visit.Path))
+		}
+	}
+
+	return directories
+}
+
+// Sort orders directories by the frecency of their visits, leaving the unvisited ones at the end in name order.
+func (h History) Sort(directories []Directory, now time.Time) []Directory {
+	sorted := slices.Clone(directories)
+
+	score := func(d Directory) float64 {
+		if visit, ok := h.visits[d.String()]; ok {
+			return visit.Frecency(now)
+		}
+
+		return 0
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		si, sj := score(sorted[i]), score(sorted[j])
+		if si == sj {
+			return sorted[i].String() < sorted[j].String()
+		}
+		return si > sj
+	})
+
+	return sorted
+}
+
+// Frecency weights the visit count by how recently the directory was visited, as zoxide does.
+func (v Visit) Frecency(now time.Time) float64 {
+	age := now.Sub(v.LastVisit)
+
+	switch {
+	case age < time.Hour:
+		return float64(v.Count) * 4
+	case age < 24*time.Hour:
+		return float64(v.Count) * 2
+	case age < 7*24*time.Hour:
+		return float64(v.Count) / 2
+	default:
+		return float64(v.Count) / 4
+	}
+}
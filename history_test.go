@@ -0,0 +1,153 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFrecency(t *testing.T) {
+	now := time.Date(2023, 10, 30, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		name  string
+		visit Visit
+		want  float64
+	}{
+		{
+			name:  "When visited within an hour",
+			visit: Visit{Path: "foo", Count: 2, LastVisit: now.Add(-time.Minute)},
+			want:  8,
+		},
+		{
+			name:  "When visited within a day",
+			visit: Visit{Path: "foo", Count: 2, LastVisit: now.Add(-2 * time.Hour)},
+			want:  4,
+		},
+		{
+			name:  "When visited within a week",
+			visit: Visit{Path: "foo", Count: 2, LastVisit: now.Add(-48 * time.Hour)},
+			want:  1,
+		},
+		{
+			name:  "When visited more than a week ago",
+			visit: Visit{Path: "foo", Count: 2, LastVisit: now.Add(-30 * 24 * time.Hour)},
+			want:  0.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.visit.Frecency(now); got != tt.want {
+				t.Errorf("visit.Frecency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRanked(t *testing.T) {
+	now := time.Date(2023, 10, 30, 12, 0, 0, 0, time.Local)
+	history := NewHistory("").
+		Add("old", now.Add(-30*24*time.Hour)).
+		Add("old", now.Add(-30*24*time.Hour)).
+		Add("recent", now.Add(-time.Minute)).
+		Add("often", now.Add(-2*time.Hour)).
+		Add("often", now.Add(-2*time.Hour)).
+		Add("often", now.Add(-2*time.Hour))
+
+	var got []string
+	for _, visit := range history.Ranked(now) {
+		got = append(got, visit.Path)
+	}
+
+	if want := []string{"often", "recent", "old"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("history.Ranked() = %v, want %v", got, want)
+	}
+}
+
+func TestHistorySort(t *testing.T) {
+	now := time.Date(2023, 10, 30, 12, 0, 0, 0, time.Local)
+	history := NewHistory("").
+		Add("/often", now.Add(-2*time.Hour)).
+		Add("/often", now.Add(-2*time.Hour)).
+		Add("/often", now.Add(-2*time.Hour)).
+		Add("/recent", now.Add(-time.Minute))
+
+	var got []string
+	for _, d := range history.Sort([]Directory{NewDirectory("/b"), NewDirectory("/recent"), NewDirectory("/a"), NewDirectory("/often")}, now) {
+		got = append(got, d.String())
+	}
+
+	if want := []string{"/often", "/recent", "/a", "/b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("history.Sort() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "arrow", "history.json")
+	now := time.Date(2023, 10, 30, 12, 0, 0, 0, time.UTC)
+
+	if err := NewHistory(path).Add("foo", now).Add("foo", now).Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := LoadHistory(path).Get()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := history.visits["foo"], (Visit{Path: "foo", Count: 2, LastVisit: now}); !got.LastVisit.Equal(want.LastVisit) || got.Count != want.Count {
+		t.Errorf("LoadHistory() = %v, want %v", got, want)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "history.json")
+
+	if err := os.WriteFile(path, []byte(`[{"path":`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeFile(path, []byte("[]")); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, err := os.ReadFile(path); err != nil || string(data) != "[]" {
+		t.Errorf("writeFile() wrote %q, want %q", data, "[]")
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("writeFile() left %d files, want only the one written", len(entries))
+	}
+}
+
+func TestHistorySave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	now := time.Date(2023, 10, 30, 12, 0, 0, 0, time.UTC)
+
+	if err := NewHistory(path).Add("/a", now).Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	first, second := LoadHistory(path).MustGet(), LoadHistory(path).MustGet()
+
+	if err := first.Add("/b", now).Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := second.Add("/a", now).Add("/c", now).Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	history := LoadHistory(path).MustGet()
+	got := map[string]int{}
+	for _, visit := range history.visits {
+		got[visit.Path] = visit.Count
+	}
+
+	if want := map[string]int{"/a": 2, "/b": 1, "/c": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Save() saved %v, want %v", got, want)
+	}
+}
@@ -10,6 +10,12 @@ var icons = map[string]string{
 	"elm-stuff":    "\ue62c",
 	".git":         "\ue5fb",
 	".github":      "\ue5fd",
+	// Projects are shown with the icon of the marker file they were found by.
+	"go.mod":         "\ue627",
+	"cargo.toml":     "\ue7a8",
+	"package.json":   "\ue718",
+	"pyproject.toml": "\ue73c",
+	"gemfile":        "\ue739",
 }
 
 func GetIcon(dir Directory, isCurrent, displayIcons bool) string {
@@ -25,6 +31,10 @@ func GetIcon(dir Directory, isCurrent, displayIcons bool) string {
 		return "\uf114 "
 	}
 
+	if val, ok := icons[strings.ToLower(dir.project)]; ok {
+		return val + " "
+	}
+
 	if val, ok := icons[strings.ToLower(dir.Name())]; ok {
 		return val + " "
 	} else {
@@ -42,6 +52,16 @@ func GetOrderIcon(order Order, displayIcons bool) string {
 		return " \uf413 "
 	case ORDER_TIME:
 		return " \ue384 "
+	case ORDER_NATURAL:
+		return " \uf162 "
+	case ORDER_NOCASE:
+		return " \uf15d "
+	case ORDER_ENTRIES:
+		return " \uf0cb "
+	case ORDER_SIZE:
+		return " \uf0a0 "
+	case ORDER_FRECENCY:
+		return " \uf1da "
 	}
 
 	return ""
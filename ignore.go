@@ -0,0 +1,211 @@
+package main
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/samber/mo"
+)
+
+// ignoreFiles are read in this order, so the rules of the later ones take precedence.
+var ignoreFiles = []string{".gitignore", ".ignore", ".arrowignore"}
+
+type ignoreRule struct {
+	// base is the directory of the ignore file, below which the rule applies.
+	base     string
+	pattern  *regexp.Regexp
+	negate   bool
+	anchored bool
+}
+
+// Ignore decides which directories are hidden by the ignore files of their ancestors, with gitignore semantics.
+type Ignore struct {
+	rules []ignoreRule
+}
+
+// LoadIgnore reads the ignore files of d and of its ancestors up to the root of the git repository containing it.
+func LoadIgnore(d Directory) Ignore {
+	chain := []Directory{d}
+
+	for current := d; !isRepositoryRoot(current); {
+		parent, ok := current.Parent().Get()
+		if !ok {
+			break
+		}
+
+		chain = append(chain, parent)
+		current = parent
+	}
+
+	ignore := Ignore{}
+	for i := len(chain) - 1; i >= 0; i-- {
+		ignore = ignore.Add(chain[i].fsys, ".", chain[i].String())
+	}
+
+	return ignore
+}
+
+func isRepositoryRoot(d Directory) bool {
+	_, err := fs.Stat(d.fsys, ".git")
+	return err == nil
+}
+
+// Add returns the rules of i followed by those of the ignore files in dir of fsys, which apply below base.
+func (i Ignore) Add(fsys fs.FS, dir string, base string) Ignore {
+	var rules []ignoreRule
+
+	for _, name := range ignoreFiles {
+		if data, err := fs.ReadFile(fsys, path.Join(dir, name)); err == nil {
+			rules = append(rules, parseIgnore(string(data), base)...)
+		}
+	}
+
+	if len(rules) == 0 {
+		return i
+	}
+
+	// Directories walked concurrently share the rules of their parent, so they are copied rather than appended to.
+	return Ignore{rules: append(slices.Clone(i.rules), rules...)}
+}
+
+func parseIgnore(content string, base string) []ignoreRule {
+	var rules []ignoreRule
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{base: base}
+
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+			line = line[1:]
+		}
+
+		// Only directories are listed, so patterns for directories only match like any other.
+		line = strings.TrimSuffix(line, "/")
+		rule.anchored = strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+
+		if line == "" {
+			continue
+		}
+
+		pattern, err := regexp.Compile(globToRegex(line))
+		if err != nil {
+			continue
+		}
+		rule.pattern = pattern
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// globToRegex translates a gitignore pattern, where ** matches across directories and * and ? do not.
+func globToRegex(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			switch {
+			case strings.HasPrefix(glob[i:], "**/"):
+				b.WriteString("(?:.*/)?")
+				i += 2
+			case strings.HasPrefix(glob[i:], "**"):
+				b.WriteString(".*")
+				i++
+			default:
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				b.WriteString(regexp.QuoteMeta(glob[i+1 : i+2]))
+				i++
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString("$")
+	return b.String()
+}
+
+// Ignored reports whether the directory at p is hidden. The last rule matching it decides, as in git.
+func (i Ignore) Ignored(p string) bool {
+	ignored := false
+
+	for _, rule := range i.rules {
+		rel, err := filepath.Rel(rule.base, p)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+
+		if !rule.anchored {
+			rel = path.Base(rel)
+		}
+
+		if rule.pattern.MatchString(rel) {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
+
+func (i Ignore) Filter(directories []Directory) []Directory {
+	if len(i.rules) == 0 {
+		return directories
+	}
+
+	var visible []Directory
+	for _, d := range directories {
+		if !i.Ignored(d.String()) {
+			visible = append(visible, d)
+		}
+	}
+
+	return visible
+}
+
+// VisibleDirs lists the child directories like Dirs, leaving out those hidden by ignore files when respectIgnore is set.
+func (d Directory) VisibleDirs(showAll bool, order Order, respectIgnore bool) mo.Result[[]Directory] {
+	directories := d.Dirs(showAll, order)
+
+	if !respectIgnore {
+		return directories
+	}
+
+	return directories.Map(func(directories []Directory) ([]Directory, error) {
+		return LoadIgnore(d).Filter(directories), nil
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/fstest"
+)
+
+func TestIgnored(t *testing.T) {
+	ignore := Ignore{rules: parseIgnore(`
+# build outputs
+node_modules/
+/dist
+packages/*/target
+**/cache
+tmp-?
+[bc]uild
+!build
+\#notes
+`, "/repo")}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "/repo/node_modules", want: true},
+		{path: "/repo/packages/a/node_modules", want: true},
+		{path: "/repo/dist", want: true},
+		{path: "/repo/src/dist", want: false},
+		{path: "/repo/packages/a/target", want: true},
+		{path: "/repo/packages/a/b/target", want: false},
+		{path: "/repo/cache", want: true},
+		{path: "/repo/src/deep/cache", want: true},
+		{path: "/repo/tmp-1", want: true},
+		{path: "/repo/tmp-10", want: false},
+		{path: "/repo/cuild", want: true},
+		{path: "/repo/build", want: false},
+		{path: "/repo/#notes", want: true},
+		{path: "/other/node_modules", want: false},
+		{path: "/repo", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := ignore.Ignored(tt.path); got != tt.want {
+				t.Errorf("Ignore.Ignored(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIgnoreNested(t *testing.T) {
+	fs := fstest.MapFS{
+		".git":                  {Mode: fs.ModeDir},
+		".gitignore":            {Data: []byte("build\nnode_modules\n")},
+		"node_modules/lib":      {Mode: fs.ModeDir},
+		"src/build":             {Mode: fs.ModeDir},
+		"src/keep/build":        {Mode: fs.ModeDir},
+		"src/keep/.arrowignore": {Data: []byte("!build\n")},
+		"pkg/a/target":          {Mode: fs.ModeDir},
+		"pkg/a/.ignore":         {Data: []byte("target\n")},
+		"pkg/b/target":          {Mode: fs.ModeDir},
+	}
+	root := Directory{path: "/repo", fsys: fs}
+
+	var got []string
+	for directories := range Walk(context.Background(), root, false, true, 0) {
+		for _, d := range directories {
+			got = append(got, d.Label())
+		}
+	}
+	sort.Strings(got)
+
+	if want := []string{"pkg", "pkg/a", "pkg/b", "pkg/b/target", "src", "src/keep", "src/keep/build"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Walk() = %v, want %v", got, want)
+	}
+
+	got = nil
+	for _, d := range root.VisibleDirs(false, ORDER_NAME, true).OrElse([]Directory{}) {
+		got = append(got, d.Name())
+	}
+
+	if want := []string{"pkg", "src"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("directory.VisibleDirs() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/samber/mo"
+)
+
+type KeyMap struct {
+	Up         key.Binding
+	Down       key.Binding
+	Parent     key.Binding
+	Open       key.Binding
+	Back       key.Binding
+	Forward    key.Binding
+	Select     key.Binding
+	Complete   key.Binding
+	Toggle     key.Binding
+	Order      key.Binding
+	Reverse    key.Binding
+	History    key.Binding
+	Bookmarks  key.Binding
+	Projects   key.Binding
+	Recursive  key.Binding
+	Preview    key.Binding
+	FullPath   key.Binding
+	SearchMode key.Binding
+	Ignored    key.Binding
+	Cancel     key.Binding
+	Help       key.Binding
+	Quit       key.Binding
+	// Insert and Normal switch the search box of a modal keymap between typing and navigating.
+	Insert key.Binding
+	Normal key.Binding
+}
+
+type Preset int
+
+const (
+	PRESET_DEFAULT Preset = iota
+	PRESET_VIM
+	PRESET_EMACS
+)
+
+var presets = map[string]Preset{
+	"default": PRESET_DEFAULT,
+	"vim":     PRESET_VIM,
+	"emacs":   PRESET_EMACS,
+}
+
+var keyNames = map[string]string{
+	"up":         "↑",
+	"down":       "↓",
+	"left":       "←",
+	"right":      "→",
+	"shift+down": "shift+↓",
+	"shift+up":   "shift+↑",
+	"alt+left":   "alt+←",
+	"alt+right":  "alt+→",
+	" ":          "space",
+}
+
+func PresetFromString(s string) mo.Result[Preset] {
+	if preset, ok := presets[s]; ok {
+		return mo.Ok(preset)
+	}
+
+	return mo.Errf[Preset]("unknown keymap %q", s)
+}
+
+func (p Preset) String() string {
+	for name, preset := range presets {
+		if preset == p {
+			return name
+		}
+	}
+
+	return ""
+}
+
+func (p Preset) KeyMap() KeyMap {
+	switch p {
+	case PRESET_VIM:
+		return VimKeyMap()
+	case PRESET_EMACS:
+		return EmacsKeyMap()
+	}
+
+	return DefaultKeyMap()
+}
+
+func newBinding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(keyHelp(keys), desc))
+}
+
+func disabledBinding(desc string) key.Binding {
+	return key.NewBinding(key.WithHelp("", desc), key.WithDisabled())
+}
+
+// keyHelp joins keys the way they are shown in the help.
+func keyHelp(keys []string) string {
+	names := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if name, ok := keyNames[k]; ok {
+			k = name
+		}
+		names = append(names, k)
+	}
+
+	return strings.Join(names, "/")
+}
+
+// DefaultKeyMap binds every action to keys that cannot be typed, as the search box always takes input.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up:         newBinding("move up", "up"),
+		Down:       newBinding("move down", "down"),
+		Parent:     newBinding("parent directory", "left"),
+		Open:       newBinding("open directory", "right"),
+		Back:       newBinding("go back", "alt+left"),
+		Forward:    newBinding("go forward", "alt+right"),
+		Select:     newBinding("select directory", "enter"),
+		Complete:   newBinding("complete path", "tab"),
+		Toggle:     newBinding("toggle selection", "tab"),
+		Order:      newBinding("change order", "shift+down"),
+		Reverse:    newBinding("reverse order", "shift+up"),
+		History:    newBinding("history", "ctrl+r"),
+		Bookmarks:  newBinding("bookmarks", "ctrl+o"),
+		Projects:   newBinding("projects", "alt+p"),
+		Recursive:  newBinding("recursive search", "ctrl+t"),
+		Preview:    newBinding("preview", "ctrl+l"),
+		FullPath:   newBinding("match full path", "ctrl+s"),
+		SearchMode: newBinding("search mode", "ctrl+x"),
+		Ignored:    newBinding("show ignored", "alt+i"),
+		Cancel:     newBinding("cancel loading", "esc"),
+		Help:       newBinding("help", "f1"),
+		Quit:       newBinding("quit", "ctrl+c"),
+		Insert:     disabledBinding("search"),
+		Normal:     disabledBinding("navigate"),
+	}
+}
+
+// VimKeyMap navigates with h/j/k/l and only types into the search box in insert mode.
+func VimKeyMap() KeyMap {
+	k := DefaultKeyMap()
+	k.Up = newBinding("move up", "up", "k")
+	k.Down = newBinding("move down", "down", "j")
+	k.Parent = newBinding("parent directory", "left", "h")
+	k.Open = newBinding("open directory", "right", "l")
+	k.Quit = newBinding("quit", "ctrl+c", "q")
+	k.Toggle = newBinding("toggle selection", "tab", " ")
+	k.Help = newBinding("help", "f1", "?")
+	k.Insert = newBinding("search", "i", "/")
+	k.Normal = newBinding("navigate", "esc")
+
+	return k
+}
+
+// EmacsKeyMap navigates with the control keys of Emacs in addition to the arrow keys.
+func EmacsKeyMap() KeyMap {
+	k := DefaultKeyMap()
+	k.Up = newBinding("move up", "up", "ctrl+p")
+	k.Down = newBinding("move down", "down", "ctrl+n")
+	k.Parent = newBinding("parent directory", "left", "ctrl+b")
+	k.Open = newBinding("open directory", "right", "ctrl+f")
+	k.Cancel = newBinding("cancel loading", "esc", "ctrl+g")
+
+	return k
+}
+
+// Modal reports whether the search box only takes input in insert mode.
+func (k KeyMap) Modal() bool {
+	return k.Insert.Enabled()
+}
+
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.Quit}
+}
+
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Parent, k.Open, k.Back, k.Forward, k.Select, k.Complete, k.Toggle},
+		{k.Order, k.Reverse, k.History, k.Bookmarks, k.Projects, k.Recursive, k.Preview, k.FullPath, k.SearchMode, k.Ignored},
+		{k.Insert, k.Normal, k.Cancel, k.Help, k.Quit},
+	}
+}
+
+// actions maps the action names used in the config file to their bindings.
+func (k *KeyMap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":          &k.Up,
+		"down":        &k.Down,
+		"parent":      &k.Parent,
+		"open":        &k.Open,
+		"back":        &k.Back,
+		"forward":     &k.Forward,
+		"select":      &k.Select,
+		"complete":    &k.Complete,
+		"toggle":      &k.Toggle,
+		"order":       &k.Order,
+		"reverse":     &k.Reverse,
+		"history":     &k.History,
+		"bookmarks":   &k.Bookmarks,
+		"projects":    &k.Projects,
+		"recursive":   &k.Recursive,
+		"preview":     &k.Preview,
+		"full_path":   &k.FullPath,
+		"search_mode": &k.SearchMode,
+		"ignored":     &k.Ignored,
+		"cancel":      &k.Cancel,
+		"help":        &k.Help,
+		"quit":        &k.Quit,
+		"insert":      &k.Insert,
+		"normal":      &k.Normal,
+	}
+}
+
+// Override rebinds the actions in keys, keeping the preset keys of the others. Invalid entries are ignored.
+func (k KeyMap) Override(keys map[string][]string) KeyMap {
+	actions := k.actions()
+
+	for action, ks := range keys {
+		if binding, ok := actions[action]; ok && len(ks) > 0 {
+			binding.SetKeys(ks...)
+			binding.SetHelp(keyHelp(ks), binding.Help().Desc)
+		}
+	}
+
+	return k
+}
+
+// Keys returns the keys bound to each action.
+func (k KeyMap) Keys() map[string][]string {
+	keys := map[string][]string{}
+	for action, binding := range k.actions() {
+		if len(binding.Keys()) > 0 {
+			keys[action] = binding.Keys()
+		}
+	}
+
+	return keys
+}
+
+func validateKeys(keys map[string][]string) []error {
+	keyMap := DefaultKeyMap()
+	actions := keyMap.actions()
+
+	var errs []error
+	for _, action := range sortedKeys(keys) {
+		if _, ok := actions[action]; !ok {
+			errs = append(errs, fmt.Errorf("keys.%s: unknown action", action))
+		} else if len(keys[action]) == 0 {
+			errs = append(errs, fmt.Errorf("keys.%s: no keys", action))
+		}
+	}
+
+	return errs
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPresetFromString(t *testing.T) {
+	tests := []struct {
+		keymap  string
+		want    Preset
+		wantErr bool
+	}{
+		{
+			keymap:  "default",
+			want:    PRESET_DEFAULT,
+			wantErr: false,
+		},
+		{
+			keymap:  "vim",
+			want:    PRESET_VIM,
+			wantErr: false,
+		},
+		{
+			keymap:  "emacs",
+			want:    PRESET_EMACS,
+			wantErr: false,
+		},
+		{
+			keymap:  "kakoune",
+			want:    PRESET_DEFAULT,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.keymap, func(t *testing.T) {
+			got := PresetFromString(tt.keymap)
+
+			if got.IsError() != tt.wantErr {
+				t.Errorf("PresetFromString(%v).IsError() = %v, want %v", tt.keymap, got.IsError(), tt.wantErr)
+			}
+
+			if got.OrEmpty() != tt.want {
+				t.Errorf("PresetFromString(%v) = %v, want %v", tt.keymap, got.OrEmpty(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPresetKeyMap(t *testing.T) {
+	tests := []struct {
+		name      string
+		preset    Preset
+		wantUp    []string
+		wantHelp  []string
+		wantModal bool
+	}{
+		{
+			name:      "When the preset is default",
+			preset:    PRESET_DEFAULT,
+			wantUp:    []string{"up"},
+			wantHelp:  []string{"f1"},
+			wantModal: false,
+		},
+		{
+			name:      "When the preset is vim",
+			preset:    PRESET_VIM,
+			wantUp:    []string{"up", "k"},
+			wantHelp:  []string{"f1", "?"},
+			wantModal: true,
+		},
+		{
+			name:      "When the preset is emacs",
+			preset:    PRESET_EMACS,
+			wantUp:    []string{"up", "ctrl+p"},
+			wantHelp:  []string{"f1"},
+			wantModal: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.preset.KeyMap()
+
+			if !reflect.DeepEqual(got.Up.Keys(), tt.wantUp) {
+				t.Errorf("KeyMap().Up.Keys() = %v, want %v", got.Up.Keys(), tt.wantUp)
+			}
+
+			if !reflect.DeepEqual(got.Help.Keys(), tt.wantHelp) {
+				t.Errorf("KeyMap().Help.Keys() = %v, want %v", got.Help.Keys(), tt.wantHelp)
+			}
+
+			if got.Modal() != tt.wantModal {
+				t.Errorf("KeyMap().Modal() = %v, want %v", got.Modal(), tt.wantModal)
+			}
+		})
+	}
+}
+
+func TestOverride(t *testing.T) {
+	got := VimKeyMap().Override(map[string][]string{"up": {"ctrl+k", "up"}, "unknown": {"x"}, "down": {}})
+
+	if want := []string{"ctrl+k", "up"}; !reflect.DeepEqual(got.Up.Keys(), want) {
+		t.Errorf("Override().Up.Keys() = %v, want %v", got.Up.Keys(), want)
+	}
+
+	if want := "ctrl+k/↑"; got.Up.Help().Key != want {
+		t.Errorf("Override().Up.Help().Key = %v, want %v", got.Up.Help().Key, want)
+	}
+
+	if want := []string{"down", "j"}; !reflect.DeepEqual(got.Down.Keys(), want) {
+		t.Errorf("Override().Down.Keys() = %v, want %v", got.Down.Keys(), want)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/mo"
+)
+
+type Layout int
+
+const (
+	LAYOUT_DEFAULT Layout = iota
+	LAYOUT_COLUMNS
+	// LAYOUT_REVERSE puts the search box at the bottom and lists the directories upwards from it.
+	LAYOUT_REVERSE
+)
+
+var layouts = map[string]Layout{
+	"default": LAYOUT_DEFAULT,
+	"columns": LAYOUT_COLUMNS,
+	"reverse": LAYOUT_REVERSE,
+}
+
+func LayoutFromString(s string) mo.Result[Layout] {
+	if layout, ok := layouts[s]; ok {
+		return mo.Ok(layout)
+	}
+
+	return mo.Errf[Layout]("unknown layout %q", s)
+}
+
+func (l Layout) String() string {
+	for name, layout := range layouts {
+		if layout == l {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// ColumnView renders a listing beside the current directory, highlighting the directory at selectedPath.
+func ColumnView(listing Listing, selectedPath string, height int, displayIcons bool) string {
+	if listing.loading {
+		return ""
+	}
+
+	selectedIndex := -1
+	for i, d := range listing.directories {
+		if d.String() == selectedPath {
+			selectedIndex = i
+			break
+		}
+	}
+
+	return DirPickerView(listing.directories, selectedIndex, height, displayIcons, mo.None[bool](), listing.err, Matcher{}, nil, nil)
+}
+
+// Height limits the picker to a number of lines, or a percentage of the terminal, rendered inline rather than on the whole screen.
+type Height struct {
+	value   int
+	percent bool
+}
+
+// HeightFromString parses N or N%. An empty string is the whole screen.
+func HeightFromString(s string) mo.Result[Height] {
+	if s == "" {
+		return mo.Ok(Height{})
+	}
+
+	number, percent := strings.CutSuffix(s, "%")
+	value, err := strconv.Atoi(number)
+
+	if err != nil || value <= 0 || (percent && value > 100) {
+		return mo.Err[Height](fmt.Errorf("invalid height %q", s))
+	}
+
+	return mo.Ok(Height{value: value, percent: percent})
+}
+
+func (h Height) String() string {
+	if h.value == 0 {
+		return ""
+	}
+
+	if h.percent {
+		return strconv.Itoa(h.value) + "%"
+	}
+
+	return strconv.Itoa(h.value)
+}
+
+// Inline reports whether the picker is rendered below the prompt instead of on the alternate screen.
+func (h Height) Inline() bool {
+	return h.value > 0
+}
+
+// Lines is the number of lines of a terminal of the given height the picker takes up.
+func (h Height) Lines(terminal int) int {
+	if !h.Inline() {
+		return terminal
+	}
+
+	lines := h.value
+	if h.percent {
+		lines = terminal * h.value / 100
+	}
+
+	return min(lines, terminal)
+}
+
+// reverseLines flips the lines of a view upside down, for the directories to be listed upwards.
+func reverseLines(view string) string {
+	lines := strings.Split(view, "\n")
+	slices.Reverse(lines)
+
+	return strings.Join(lines, "\n")
+}
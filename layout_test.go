@@ -0,0 +1,107 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestLayoutFromString(t *testing.T) {
+	tests := []struct {
+		layout  string
+		want    Layout
+		wantErr bool
+	}{
+		{
+			layout:  "default",
+			want:    LAYOUT_DEFAULT,
+			wantErr: false,
+		},
+		{
+			layout:  "columns",
+			want:    LAYOUT_COLUMNS,
+			wantErr: false,
+		},
+		{
+			layout:  "reverse",
+			want:    LAYOUT_REVERSE,
+			wantErr: false,
+		},
+		{
+			layout:  "rows",
+			want:    LAYOUT_DEFAULT,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			got := LayoutFromString(tt.layout)
+
+			if got.IsError() != tt.wantErr {
+				t.Errorf("LayoutFromString(%v).IsError() = %v, want %v", tt.layout, got.IsError(), tt.wantErr)
+			}
+
+			if got.OrEmpty() != tt.want {
+				t.Errorf("LayoutFromString(%v) = %v, want %v", tt.layout, got.OrEmpty(), tt.want)
+			}
+		})
+	}
+}
+
+func TestHeightLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		height   string
+		terminal int
+		want     int
+		wantErr  bool
+	}{
+		{
+			name:     "When the height is not set",
+			height:   "",
+			terminal: 40,
+			want:     40,
+		},
+		{
+			name:     "When the height is a number of lines",
+			height:   "15",
+			terminal: 40,
+			want:     15,
+		},
+		{
+			name:     "When the height is a percentage",
+			height:   "40%",
+			terminal: 40,
+			want:     16,
+		},
+		{
+			name:     "When the height exceeds the terminal",
+			height:   "100",
+			terminal: 40,
+			want:     40,
+		},
+		{
+			name:    "When the percentage exceeds 100",
+			height:  "120%",
+			wantErr: true,
+		},
+		{
+			name:    "When the height is not a number",
+			height:  "half",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			height, err := HeightFromString(tt.height).Get()
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HeightFromString(%q) error = %v, wantErr %v", tt.height, err, tt.wantErr)
+			}
+
+			if got := height.Lines(tt.terminal); !tt.wantErr && got != tt.want {
+				t.Errorf("Lines(%d) = %d, want %d", tt.terminal, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/samber/mo"
+)
+
+type loadRequest struct {
+	directory Directory
+	// cursorPath is selected once the directories are loaded.
+	cursorPath string
+	// keepIfEmpty stays in the current directory when the requested one has no child directories.
+	keepIfEmpty bool
+	clearQuery  bool
+	// query replaces the one in the search box once the directories are loaded.
+	query  mo.Option[string]
+	travel Travel
+}
+
+// startMsg lists the current directory once the picker has started, so that a slow directory does not hold up the first frame.
+type startMsg struct{}
+
+type loadedMsg struct {
+	id          int
+	request     loadRequest
+	directories []Directory
+	err         error
+}
+
+// loadDirectories lists the requested directory in the background.
+// Reading a directory cannot be interrupted, so a cancelled load returns immediately and leaves the read to finish on its own.
+func loadDirectories(ctx context.Context, id int, request loadRequest, showAll bool, order Order, respectIgnore bool) tea.Cmd {
+	return func() tea.Msg {
+		result := make(chan mo.Result[[]Directory], 1)
+		go func() {
+			result <- request.directory.VisibleDirs(showAll, order, respectIgnore)
+		}()
+
+		select {
+		case <-ctx.Done():
+			return loadedMsg{id: id, request: request, err: ctx.Err()}
+		case r := <-result:
+			directories, err := r.Get()
+			return loadedMsg{id: id, request: request, directories: directories, err: err}
+		}
+	}
+}
+
+type historyMsg struct {
+	id          int
+	directories []Directory
+}
+
+// loadHistory lists the visited directories that still exist in the background, as checking them can be slow on network mounts.
+func loadHistory(ctx context.Context, id int, history History) tea.Cmd {
+	return func() tea.Msg {
+		result := make(chan []Directory, 1)
+		go func() {
+			result <- history.Directories(time.Now())
+		}()
+
+		select {
+		case <-ctx.Done():
+			return historyMsg{id: id}
+		case directories := <-result:
+			return historyMsg{id: id, directories: directories}
+		}
+	}
+}
+
+// Listing is the child directories of a directory shown beside the current one.
+type Listing struct {
+	directories []Directory
+	loading     bool
+	err         error
+}
+
+type listingMsg struct {
+	path    string
+	listing Listing
+}
+
+func loadListing(d Directory, showAll bool, order Order, respectIgnore bool) tea.Cmd {
+	return func() tea.Msg {
+		directories, err := d.VisibleDirs(showAll, order, respectIgnore).Get()
+		return listingMsg{path: d.String(), listing: Listing{directories: directories, err: err}}
+	}
+}
@@ -1,17 +1,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
-	"math"
 	"os"
+	"slices"
 	"strconv"
-	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/lithammer/fuzzysearch/fuzzy"
 	zone "github.com/lrstanley/bubblezone"
 	"github.com/muesli/termenv"
 	"github.com/samber/mo"
@@ -28,14 +32,33 @@ type Styles struct {
 }
 
 var (
-	styles = Styles{
+	styles = newStyles(colors)
+)
+
+func newStyles(c Colors) Styles {
+	return Styles{
 		Header: lipgloss.DefaultRenderer().NewStyle().PaddingBottom(1),
-		Count:  lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(DisabledColor)),
+		Count:  lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(c.Disabled)),
 		CurrentDirectory: lipgloss.DefaultRenderer().NewStyle().
-			Bold(true).BorderForeground(lipgloss.Color(BorderColor)).BorderStyle(lipgloss.NormalBorder()).Foreground(lipgloss.Color(CurrentDirectoryColor)),
-		Prompt:     lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(PromptColor)),
-		Foreground: lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(ForegroundColor)),
+			Bold(true).BorderForeground(lipgloss.Color(c.Border)).BorderStyle(lipgloss.NormalBorder()).Foreground(lipgloss.Color(c.CurrentDirectory)),
+		Prompt:     lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(c.Prompt)),
+		Foreground: lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(c.Foreground)),
 	}
+}
+
+const (
+	EXIT_SELECTED = iota
+	EXIT_CANCELLED
+	EXIT_ERROR
+)
+
+type Mode int
+
+const (
+	MODE_BROWSE Mode = iota
+	MODE_HISTORY
+	MODE_BOOKMARK
+	MODE_PROJECTS
 )
 
 type model struct {
@@ -46,30 +69,255 @@ type model struct {
 	filteredDirectories []Directory
 	textInput           textinput.Model
 	height              int
+	width               int
+	maxHeight           Height
 	showAll             bool
 	displayIcons        bool
 	order               Order
+	reverse             bool
+	fullPath            bool
+	searchMode          SearchMode
+	respectIgnore       bool
+	sizes               map[string]int64
+	sizeCancel          context.CancelFunc
+	gitStatuses         map[string]mo.Option[GitStatus]
+	mode                Mode
+	history             History
+	bookmarks           Bookmarks
+	projectRoots        []Directory
+	projectDepth        int
+	projects            []Project
+	scanning            bool
+	recursive           bool
+	depth               int
+	walkID              int
+	walk                <-chan []Directory
+	walkCancel          context.CancelFunc
+	cursorPath          string
+	loadID              int
+	loadCancel          context.CancelFunc
+	spinner             spinner.Model
+	watcher             *Watcher
+	showPreview         bool
+	previews            map[string]Preview
+	layout              Layout
+	listings            map[string]Listing
+	keys                KeyMap
+	navigation          Navigation
+	multi               bool
+	marked              []mark
+	printer             Printer
+	insert              bool
+	showHelp            bool
+	help                help.Model
+	selected            mo.Option[Directory]
+	cancelled           bool
 	err                 error
 }
 
+type Options struct {
+	Query         string
+	ShowAll       bool
+	DisplayIcons  bool
+	ShowHistory   bool
+	ShowProjects  bool
+	Projects      Projects
+	Recursive     bool
+	Depth         int
+	ShowPreview   bool
+	Layout        Layout
+	Height        Height
+	Order         Order
+	Reverse       bool
+	FullPath      bool
+	SearchMode    SearchMode
+	RespectIgnore bool
+	KeyMap        KeyMap
+	Multi         bool
+	Printer       Printer
+}
+
 func (m model) Init() tea.Cmd {
-	return textinput.Blink
+	cmds := []tea.Cmd{textinput.Blink}
+
+	if m.listsInBackground() {
+		cmds = append(cmds, func() tea.Msg { return startMsg{} })
+	}
+
+	if m.walk != nil {
+		cmds = append(cmds, waitForWalk(m.walkID, m.walk), m.spinner.Tick)
+	}
+
+	if m.watcher != nil {
+		cmds = append(cmds, m.watcher.Wait())
+	}
+
+	if m.scanning {
+		cmds = append(cmds, scanProjects(m.projectRoots, m.projectDepth), m.spinner.Tick)
+	}
+
+	return tea.Batch(cmds...)
 }
 
 func (m model) View() string {
+	// The last frame stays on the terminal when rendering inline, so it is cleared on exit.
+	if m.maxHeight.Inline() && (m.cancelled || m.selected.IsPresent()) {
+		return ""
+	}
+
 	order := GetOrderIcon(m.order, m.displayIcons)
+	header := []string{
+		styles.CurrentDirectory.Render(zone.Mark("order", order) + m.selectedDirectoryPath().OrElse(m.currentDirectory.String()) + " "),
+		m.textInput.View(),
+		styles.Count.Render(m.countView()),
+	}
+	height := max(m.maxHeight.Lines(m.height)-lipgloss.Height(lipgloss.JoinVertical(lipgloss.Top, header...)), 0)
+	view := append(header, m.pickerView(height))
+
+	if m.layout == LAYOUT_REVERSE {
+		slices.Reverse(view)
+	}
+
+	return zone.Scan(lipgloss.JoinVertical(lipgloss.Top, view...))
+}
+
+// pickerView renders the directories in height lines, filling them all unless the picker takes up the whole screen
+// so that an inline picker keeps its size and the search box of the reverse layout stays at the bottom.
+func (m model) pickerView(height int) string {
+	view := m.listView(height)
+
+	switch {
+	case m.layout == LAYOUT_REVERSE:
+		return lipgloss.PlaceVertical(height, lipgloss.Bottom, view)
+	case m.maxHeight.Inline():
+		return lipgloss.PlaceVertical(height, lipgloss.Top, view)
+	}
+
+	return view
+}
+
+func (m model) listView(height int) string {
+	if m.showHelp {
+		return m.help.FullHelpView(m.keys.FullHelp())
+	}
+	matcher := m.matcher().OrEmpty()
+	if m.pathQuery().IsPresent() {
+		matcher = Matcher{}
+	}
+	var marked map[string]bool
+	if m.multi {
+		marked = map[string]bool{}
+		for _, mk := range m.marked {
+			marked[mk.directory.String()] = true
+		}
+	}
+	picker := DirPickerView(m.filteredDirectories, m.cursor, height, m.displayIcons, m.hasChildDirectory, m.err, matcher, m.gitStatuses, marked)
+
+	if m.layout == LAYOUT_REVERSE {
+		picker = reverseLines(picker)
+	}
+
+	if m.width == 0 {
+		return picker
+	}
+
+	if m.layout == LAYOUT_COLUMNS {
+		return m.columnsView(picker, height)
+	}
+
+	if !m.showPreview {
+		return picker
+	}
+
+	content := ""
+	m.selectedDirectory().ForEach(func(d Directory) {
+		content = PreviewView(m.previews[d.String()], height)
+	})
+
+	width := m.width / 2
+	return lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		lipgloss.NewStyle().Width(width).MaxWidth(width).Render(picker),
+		previewStyles.Pane.MaxWidth(m.width-width).Render(content))
+}
+
+// columnsView renders the parent directory, the current directory and the directory under the cursor side by side.
+func (m model) columnsView(picker string, height int) string {
+	parent, child := "", ""
+
+	if m.mode == MODE_BROWSE {
+		m.currentDirectory.Parent().ForEach(func(d Directory) {
+			parent = ColumnView(m.listings[d.String()], m.currentDirectory.String(), height, m.displayIcons)
+		})
+	}
+
+	m.selectedDirectory().ForEach(func(d Directory) {
+		child = ColumnView(m.listings[d.String()], "", height, m.displayIcons)
+	})
 
-	return zone.Scan(
-		lipgloss.JoinVertical(
-			lipgloss.Top,
-			styles.CurrentDirectory.Render(zone.Mark("order", order)+m.selectedDirectoryPath().OrElse(m.currentDirectory.String())+" "),
-			m.textInput.View(),
-			styles.Count.Render(m.countView()),
-			DirPickerView(m.filteredDirectories, m.cursor, int(math.Max(float64(m.height-8), float64(0))), m.displayIcons, m.hasChildDirectory, m.err)))
+	width := m.width / 3
+	column := lipgloss.NewStyle().Width(width).MaxWidth(width)
+
+	return lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		column.Render(parent),
+		column.Render(picker),
+		column.Render(child))
 }
 
 func (m model) countView() string {
-	return fmt.Sprintf("  %s/%s", strconv.Itoa(len(m.filteredDirectories)), strconv.Itoa(len(m.directories)))
+	count := fmt.Sprintf("  %s/%s", strconv.Itoa(len(m.filteredDirectories)), strconv.Itoa(len(m.directories)))
+
+	switch m.mode {
+	case MODE_HISTORY:
+		count += " history"
+	case MODE_BOOKMARK:
+		count += " bookmarks"
+	case MODE_PROJECTS:
+		count += " projects"
+	}
+
+	if m.recursive && m.mode == MODE_BROWSE {
+		count += " recursive"
+	}
+
+	if m.order != ORDER_NAME && m.mode == MODE_BROWSE {
+		count += " by " + m.order.String()
+	}
+
+	if m.reverse && m.mode == MODE_BROWSE {
+		count += " reversed"
+	}
+
+	if m.fullPath {
+		count += " full path"
+	}
+
+	if m.pathQuery().IsPresent() {
+		count += " path"
+	}
+
+	if len(m.marked) > 0 {
+		count += fmt.Sprintf(" %d selected", len(m.marked))
+	}
+
+	if m.respectIgnore && m.mode == MODE_BROWSE {
+		count += " ignoring"
+	}
+
+	if m.loading() {
+		count += " " + m.spinner.View()
+	}
+
+	return count
+}
+
+func (m model) selectedDirectory() mo.Option[Directory] {
+	if len(m.filteredDirectories) > m.cursor {
+		return mo.Some(m.filteredDirectories[m.cursor])
+	}
+
+	return mo.None[Directory]()
 }
 
 func (m model) selectedDirectoryPath() mo.Option[string] {
@@ -80,74 +328,614 @@ func (m model) selectedDirectoryPath() mo.Option[string] {
 	return mo.None[string]()
 }
 
-func filterDirectories(directories []Directory, query string) []Directory {
-	var filteredDirectories []Directory
+// matcher matches the query typed in the search box.
+func (m model) matcher() mo.Result[Matcher] {
+	return NewMatcher(m.textInput.Value(), m.fullPath, m.searchMode)
+}
 
-	if strings.Trim(query, "") == "" {
-		return directories
+// pathQuery is the query when a path is typed into the search box.
+func (m model) pathQuery() mo.Option[PathQuery] {
+	if !IsPathQuery(m.textInput.Value()) {
+		return mo.None[PathQuery]()
 	}
 
-	for _, directory := range directories {
-		if fuzzy.MatchNormalizedFold(query, directory.String()) {
-			filteredDirectories = append(filteredDirectories, directory)
-		}
+	previous := os.Getenv("OLDPWD")
+	m.navigation.Back().ForEach(func(l location) {
+		previous = l.directory.String()
+	})
+
+	return mo.Some(ParsePathQuery(m.textInput.Value(), m.currentDirectory.String(), previous))
+}
+
+// filter applies the query to the listed directories, reporting an invalid pattern through m.err.
+// A path lists the directories it can be completed to instead.
+func (m model) filter() model {
+	if p, ok := m.pathQuery().Get(); ok {
+		m.filteredDirectories = p.Candidates()
+		return m
 	}
 
-	return filteredDirectories
+	matcher, err := m.matcher().Get()
+
+	if err != nil {
+		m.err = err
+		m.filteredDirectories = []Directory{}
+		return m
+	}
+
+	m.filteredDirectories = matcher.Filter(m.directories)
+	return m
 }
 
-func (m model) changeOrder() (tea.Model, tea.Cmd) {
+func (m model) listDirectories() mo.Result[[]Directory] {
+	switch m.mode {
+	case MODE_HISTORY:
+		return mo.Ok(m.history.Directories(time.Now()))
+	case MODE_BOOKMARK:
+		return mo.Ok(m.bookmarks.Directories())
+	case MODE_PROJECTS:
+		return mo.Ok(projectDirectories(m.projects))
+	}
+
+	if m.recursive {
+		// Filled in by the walk started in startWalk.
+		return mo.Ok([]Directory{})
+	}
+
+	return m.currentDirectory.VisibleDirs(m.showAll, m.order, m.respectIgnore).Map(func(directories []Directory) ([]Directory, error) {
+		return m.arrange(directories), nil
+	})
+}
+
+// arrange applies the orders that depend on the state of the picker, and the reverse toggle, to a listing.
+func (m model) arrange(directories []Directory) []Directory {
 	switch m.order {
-	case ORDER_NAME:
-		m.order = ORDER_TIME
-	case ORDER_TIME:
-		m.order = ORDER_NAME
+	case ORDER_SIZE:
+		directories = SortBySize(directories, m.sizes)
+	case ORDER_FRECENCY:
+		directories = m.history.Sort(directories, time.Now())
+	}
+
+	if m.reverse {
+		directories = Reversed(directories)
+	}
+
+	return directories
+}
+
+// sizing reports whether the sizes of listed directories are still being measured.
+func (m model) sizing() bool {
+	if m.order != ORDER_SIZE {
+		return false
+	}
+
+	for _, d := range m.directories {
+		if m.sizes[d.String()] < 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reorder replaces the listed directories with reordered ones, keeping the cursor on the selected directory.
+func (m model) reorder(directories []Directory) model {
+	selected := m.selectedDirectoryPath().OrElse("")
+	m.directories = directories
+	m = m.filter()
+	m.cursor = 0
+
+	for i, d := range m.filteredDirectories {
+		if d.String() == selected {
+			m.cursor = i
+			break
+		}
+	}
+
+	return m
+}
+
+// load lists the requested directory in the background, superseding any load in progress.
+func (m model) load(request loadRequest) (tea.Model, tea.Cmd) {
+	m = m.cancel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.loadID++
+	m.loadCancel = cancel
+
+	return m, tea.Batch(loadDirectories(ctx, m.loadID, request, m.showAll, m.order, m.respectIgnore), m.spinner.Tick)
+}
+
+func (m model) loaded(msg loadedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
 	}
 
-	m.directories = m.currentDirectory.Dirs(m.showAll, m.order).Map(func(value []Directory) ([]Directory, error) {
-		return value, nil
-	}).MapErr(func(err error) ([]Directory, error) {
+	if len(msg.directories) == 0 && msg.request.keepIfEmpty {
+		m.hasChildDirectory = mo.Some(false)
+		return m, nil
+	}
+
+	if msg.request.directory.String() != m.currentDirectory.String() {
+		m.navigation = m.navigation.Leave(m.location(), msg.request.travel)
+	}
+
+	if msg.request.clearQuery {
+		m.textInput.SetValue("")
+	}
+
+	msg.request.query.ForEach(func(query string) {
+		m.textInput.SetValue(query)
+	})
+
+	m.currentDirectory = msg.request.directory
+	m = m.watch()
+	// The directories previewed may have changed since, and are read again when the cursor reaches them.
+	m.previews = map[string]Preview{}
+	m.mode = MODE_BROWSE
+	m.directories = m.arrange(msg.directories)
+	m = m.filter()
+	m.cursor = 0
+
+	for i, d := range m.filteredDirectories {
+		if d.String() == msg.request.cursorPath {
+			m.cursor = i
+			break
+		}
+	}
+
+	next, cmd := m.refresh()
+	m = next.(model)
+
+	if m.walk != nil {
+		// The recursive results are listed as the walk goes, and the cursor is restored once the selected one is.
+		m.cursorPath = msg.request.cursorPath
+		return m, cmd
+	}
+
+	m, measure := m.measure()
+	return m, tea.Batch(cmd, measure)
+}
+
+// measure starts measuring the sizes not known yet of the listed directories when they are ordered by size.
+func (m model) measure() (model, tea.Cmd) {
+	if m.order != ORDER_SIZE || m.mode != MODE_BROWSE || m.recursive {
+		return m, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var cmds []tea.Cmd
+
+	for _, d := range m.directories {
+		if _, measured := m.sizes[d.String()]; !measured {
+			m.sizes[d.String()] = -1
+			cmds = append(cmds, loadSize(ctx, d))
+		}
+	}
+
+	if len(cmds) == 0 {
+		cancel()
+		return m, nil
+	}
+
+	m.sizeCancel = cancel
+	return m, tea.Batch(append(cmds, m.spinner.Tick)...)
+}
+
+func (m model) watch() model {
+	if m.watcher == nil {
+		return m
+	}
+
+	if err := m.watcher.Watch(m.currentDirectory.String()); err != nil {
+		m.err = err
+	}
+
+	return m
+}
+
+// cancel stops the directory load, the recursive walk and the measuring of sizes in progress.
+func (m model) cancel() model {
+	if m.sizeCancel != nil {
+		m.sizeCancel()
+		m.sizeCancel = nil
+
+		// The sizes being measured are measured again the next time the directories are listed.
+		for path, size := range m.sizes {
+			if size < 0 {
+				delete(m.sizes, path)
+			}
+		}
+	}
+
+	if m.loadCancel != nil {
+		m.loadCancel()
+		m.loadCancel = nil
+	}
+
+	if m.walkCancel != nil {
+		m.walkCancel()
+		m.walkCancel = nil
+		m.walk = nil
+	}
+
+	return m
+}
+
+func (m model) loading() bool {
+	return m.loadCancel != nil || m.walk != nil || m.scanning || m.sizing()
+}
+
+// refresh restarts the recursive walk below the current directory when recursive search is enabled.
+func (m model) refresh() (tea.Model, tea.Cmd) {
+	m = m.cancel()
+
+	if !m.recursive || m.mode != MODE_BROWSE {
+		return m, nil
+	}
+
+	m = m.startWalk()
+
+	return m, tea.Batch(waitForWalk(m.walkID, m.walk), m.spinner.Tick)
+}
+
+func (m model) startWalk() model {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.walkID++
+	m.walkCancel = cancel
+	m.walk = Walk(ctx, m.currentDirectory, m.showAll, m.respectIgnore, m.depth)
+	m.directories = []Directory{}
+	m.filteredDirectories = []Directory{}
+	m.cursor = 0
+
+	return m
+}
+
+func (m model) toggleRecursive() (tea.Model, tea.Cmd) {
+	m.recursive = !m.recursive
+
+	return m.changeMode(MODE_BROWSE)
+}
+
+func (m model) changeMode(mode Mode) (tea.Model, tea.Cmd) {
+	m.hasChildDirectory = mo.None[bool]()
+
+	if mode == MODE_BROWSE {
+		return m.load(loadRequest{directory: m.currentDirectory})
+	}
+
+	m = m.cancel()
+	m.cursor = 0
+	m.mode = mode
+
+	if mode == MODE_HISTORY {
+		ctx, cancel := context.WithCancel(context.Background())
+		m.loadID++
+		m.loadCancel = cancel
+		m.directories = []Directory{}
+		m = m.filter()
+
+		return m, tea.Batch(loadHistory(ctx, m.loadID, m.history), m.spinner.Tick)
+	}
+
+	// The projects the cache knows of are listed at once, until the scan finds them all.
+	if mode == MODE_PROJECTS && m.projects == nil {
+		m.projects = loadProjectCache().Projects(m.projectRoots, m.projectDepth)
+	}
+
+	m.directories = m.listDirectories().MapErr(func(err error) ([]Directory, error) {
 		m.err = err
 		return []Directory{}, err
 	}).OrElse([]Directory{})
-	m.filteredDirectories = m.directories
+	m = m.filter()
+
+	if mode == MODE_PROJECTS {
+		return m.scanProjects()
+	}
+
+	return m, nil
+}
+
+// scanProjects lists the projects the cache knows of at once, and revalidates it in the background.
+func (m model) scanProjects() (tea.Model, tea.Cmd) {
+	m.scanning = true
+
+	return m, tea.Batch(scanProjects(m.projectRoots, m.projectDepth), m.spinner.Tick)
+}
+
+func (m model) changeOrder() (tea.Model, tea.Cmd) {
+	m.order = m.order.Next()
+	m.listings = map[string]Listing{}
+
+	if m.mode != MODE_BROWSE {
+		return m, nil
+	}
+
+	return m.load(loadRequest{directory: m.currentDirectory, cursorPath: m.selectedDirectoryPath().OrElse("")})
+}
+
+func (m model) toggleReverse() (tea.Model, tea.Cmd) {
+	m.reverse = !m.reverse
+	m.listings = map[string]Listing{}
+
+	// History and bookmarks keep their own order, and recursive results the order they are found in.
+	if m.mode != MODE_BROWSE || m.recursive {
+		return m, nil
+	}
+
+	return m.reorder(Reversed(m.directories)), nil
+}
+
+func (m model) toggleIgnored() (tea.Model, tea.Cmd) {
+	m.respectIgnore = !m.respectIgnore
+	m.listings = map[string]Listing{}
+
+	if m.mode != MODE_BROWSE {
+		return m, nil
+	}
+
+	return m.load(loadRequest{directory: m.currentDirectory, cursorPath: m.selectedDirectoryPath().OrElse("")})
+}
+
+// location is where the picker is, to come back to it later.
+func (m model) location() location {
+	if m.mode != MODE_BROWSE {
+		return location{directory: m.currentDirectory}
+	}
+
+	return location{directory: m.currentDirectory, cursorPath: m.selectedDirectoryPath().OrElse(""), query: m.textInput.Value()}
+}
+
+// travel goes back or forward to a directory left earlier, restoring the selection and the query.
+func (m model) travel(travel Travel) (tea.Model, tea.Cmd) {
+	to := m.navigation.Back()
+	if travel == TRAVEL_FORWARD {
+		to = m.navigation.Forward()
+	}
+
+	l, ok := to.Get()
+	if !ok {
+		return m, nil
+	}
+
+	m.hasChildDirectory = mo.None[bool]()
+	return m.load(loadRequest{directory: l.directory, cursorPath: l.cursorPath, query: mo.Some(l.query), travel: travel})
+}
+
+// jump moves to the directory of a typed path, or to the one under the cursor if the path is only the beginning
+// of its name or the cursor has been moved onto it.
+func (m model) jump(p PathQuery) (tea.Model, tea.Cmd) {
+	target := NewDirectory(p.Target())
+
+	if info, err := os.Stat(target.String()); err != nil || !info.IsDir() || m.cursor > 0 {
+		d, ok := m.selectedDirectory().Get()
+		if !ok {
+			m.err = fmt.Errorf("%s: no such directory", target.String())
+			return m, nil
+		}
+
+		target = d
+	}
+
+	m.hasChildDirectory = mo.None[bool]()
+	return m.load(loadRequest{directory: target, clearQuery: true})
+}
+
+// moveCursor moves the cursor by delta directories, stopping at the first and the last.
+func (m model) moveCursor(delta int) model {
+	m.hasChildDirectory = mo.None[bool]()
+	m.cursor = max(min(m.cursor+delta, len(m.filteredDirectories)-1), 0)
+
+	return m
+}
+
+func (m model) moveTo(d Directory) (tea.Model, tea.Cmd) {
+	m.hasChildDirectory = mo.None[bool]()
+	if len(m.filteredDirectories)-1 < m.cursor {
+		return m, nil
+	}
+
+	return m.load(loadRequest{directory: d, keepIfEmpty: true, clearQuery: true})
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+
+	return next.(model).prefetch(cmd)
+}
+
+// prefetch loads what the preview pane and the columns layout show for the cursor position, and the git status of the directories in view, unless it has already been loaded.
+func (m model) prefetch(cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	cmds := []tea.Cmd{cmd}
+	selected, ok := m.selectedDirectory().Get()
+
+	if m.showPreview && ok {
+		if _, loaded := m.previews[selected.String()]; !loaded {
+			m.previews[selected.String()] = Preview{loading: true}
+			cmds = append(cmds, loadPreview(selected, m.showAll))
+		}
+	}
+
+	// Only the directories in view are looked at, as there can be too many repositories to run git for each of them.
+	start, end := visibleWindow(len(m.filteredDirectories), m.cursor, m.maxHeight.Lines(m.height))
+	for _, d := range m.filteredDirectories[start:end] {
+		if _, loaded := m.gitStatuses[d.String()]; !loaded {
+			m.gitStatuses[d.String()] = mo.None[GitStatus]()
+			cmds = append(cmds, loadGitStatus(d))
+		}
+	}
+
+	if m.layout == LAYOUT_COLUMNS {
+		if ok {
+			cmds = append(cmds, m.list(selected))
+		}
+
+		m.currentDirectory.Parent().ForEach(func(parent Directory) {
+			cmds = append(cmds, m.list(parent))
+		})
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m model) list(d Directory) tea.Cmd {
+	if _, loaded := m.listings[d.String()]; loaded {
+		return nil
+	}
+
+	m.listings[d.String()] = Listing{loading: true}
+	return loadListing(d, m.showAll, m.order, m.respectIgnore)
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		m.err = nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.width = msg.Width
+		m.help.Width = msg.Width
+		return m, nil
+
+	case previewMsg:
+		m.previews[msg.path] = msg.preview
+		return m, nil
+
+	case listingMsg:
+		msg.listing.directories = m.arrange(msg.listing.directories)
+		m.listings[msg.path] = msg.listing
+		return m, nil
+
+	case projectsMsg:
+		m.scanning = false
+		m.projects = msg.projects
+		m.err = msg.err
+
+		if m.mode != MODE_PROJECTS {
+			return m, nil
+		}
+
+		return m.reorder(projectDirectories(m.projects)), nil
+
+	case gitMsg:
+		m.gitStatuses[msg.path] = msg.status
+		return m, nil
+
+	case sizeMsg:
+		size, err := msg.size.Get()
+		if err != nil {
+			// Cancelled, and left unknown until the directory is listed again.
+			return m, nil
+		}
+
+		m.sizes[msg.path] = size
+
+		if m.sizeCancel != nil && !m.sizing() {
+			m.sizeCancel()
+			m.sizeCancel = nil
+		}
+
+		if m.order != ORDER_SIZE || m.mode != MODE_BROWSE || m.recursive {
+			return m, nil
+		}
+
+		next := m.reorder(m.arrange(m.directories))
+
+		// A cursor left at the top stays there, on the largest directory.
+		if m.cursor == 0 {
+			next.cursor = 0
+		}
+
+		return next, nil
+
+	case startMsg:
+		if m.mode == MODE_HISTORY {
+			return m.changeMode(MODE_HISTORY)
+		}
+
+		return m.load(loadRequest{directory: m.currentDirectory})
+
+	case historyMsg:
+		if msg.id != m.loadID || m.loadCancel == nil || m.mode != MODE_HISTORY {
+			return m, nil
+		}
+
+		m.loadCancel = nil
+		m.directories = msg.directories
+		m = m.filter()
+		m.cursor = 0
+		return m, nil
+
+	case loadedMsg:
+		if msg.id != m.loadID || m.loadCancel == nil {
+			return m, nil
+		}
+
+		m.loadCancel = nil
+		return m.loaded(msg)
+
+	case watchMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, m.watcher.Wait()
+		}
+
+		// Recursive results and loads already in flight are left alone rather than restarted.
+		if msg.path != m.currentDirectory.String() || m.mode != MODE_BROWSE || m.recursive || m.loadCancel != nil {
+			return m, m.watcher.Wait()
+		}
 
-	return m, nil
-}
+		next, cmd := m.load(loadRequest{directory: m.currentDirectory, cursorPath: m.selectedDirectoryPath().OrElse("")})
+		return next, tea.Batch(cmd, m.watcher.Wait())
 
-func (m model) moveTo(d Directory) (tea.Model, tea.Cmd) {
-	m.hasChildDirectory = mo.None[bool]()
-	if len(m.filteredDirectories)-1 < m.cursor {
-		return m, nil
-	}
+	case spinner.TickMsg:
+		if !m.loading() {
+			return m, nil
+		}
+
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
 
-	m.textInput.SetValue("")
-	m.currentDirectory = d
-	m.directories = m.currentDirectory.Dirs(m.showAll, m.order).Map(func(value []Directory) ([]Directory, error) {
-		if len(value) == 0 {
-			m.hasChildDirectory = mo.Some(false)
-			return m.directories, nil
+	case walkMsg:
+		if msg.id != m.walkID || m.walk == nil {
+			return m, nil
 		}
 
-		m.cursor = 0
-		return value, nil
-	}).MapErr(func(err error) ([]Directory, error) {
-		m.cursor = 0
-		return []Directory{}, err
-	}).OrElse([]Directory{})
-	m.filteredDirectories = m.directories
-	return m, nil
-}
+		if msg.done {
+			m.walkCancel()
+			m.walk, m.walkCancel = nil, nil
+			return m, nil
+		}
 
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	m.err = nil
+		m.directories = append(m.directories, msg.directories...)
+		m = m.filter()
 
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		m.height = msg.Height
-		return m, nil
+		if m.cursorPath != "" {
+			for i, d := range m.filteredDirectories {
+				if d.String() == m.cursorPath {
+					m.cursor, m.cursorPath = i, ""
+					break
+				}
+			}
+		}
+
+		return m, waitForWalk(m.walkID, m.walk)
 
 	case tea.MouseMsg:
+		if msg.Action == tea.MouseActionPress {
+			switch msg.Button {
+			case tea.MouseButtonBackward:
+				return m.travel(TRAVEL_BACK)
+			case tea.MouseButtonForward:
+				return m.travel(TRAVEL_FORWARD)
+			}
+		}
+
 		if msg.Type != tea.MouseLeft {
 			return m, nil
 		}
@@ -167,68 +955,156 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyCtrlC:
-			wd, err := os.Getwd()
+		if m.showHelp && !key.Matches(msg, m.keys.Quit) {
+			m.showHelp = false
+			return m, nil
+		}
+
+		if m.keys.Modal() {
+			if m.insert && key.Matches(msg, m.keys.Normal) {
+				m.insert = false
+				m.textInput.Blur()
+				return m, nil
+			}
 
-			if err != nil {
-				panic(err)
+			if !m.insert && key.Matches(msg, m.keys.Insert) {
+				m.insert = true
+				return m, m.textInput.Focus()
 			}
 
-			fmt.Println(wd)
+			// Printable keys are typed into the search box in insert mode, even when they are bound.
+			if m.insert && (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) {
+				break
+			}
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			m.cancelled = true
 			return m, tea.Quit
 
-		case tea.KeyUp:
+		case key.Matches(msg, m.keys.Up):
+			// The reverse layout lists the directories upwards, so moving up goes to the next one.
+			if m.layout == LAYOUT_REVERSE {
+				return m.moveCursor(1), nil
+			}
+
+			return m.moveCursor(-1), nil
+
+		case key.Matches(msg, m.keys.Down):
+			if m.layout == LAYOUT_REVERSE {
+				return m.moveCursor(-1), nil
+			}
+
+			return m.moveCursor(1), nil
+
+		case key.Matches(msg, m.keys.Back):
+			return m.travel(TRAVEL_BACK)
+
+		case key.Matches(msg, m.keys.Forward):
+			return m.travel(TRAVEL_FORWARD)
+
+		case key.Matches(msg, m.keys.Parent):
+			if m.mode != MODE_BROWSE {
+				return m.changeMode(MODE_BROWSE)
+			}
+
 			m.hasChildDirectory = mo.None[bool]()
-			if m.cursor > 0 {
-				m.cursor--
+			parent, ok := m.currentDirectory.Parent().Get()
+
+			if !ok {
+				return m, nil
+			}
+
+			return m.load(loadRequest{directory: parent, cursorPath: m.currentDirectory.String(), clearQuery: true})
+
+		case key.Matches(msg, m.keys.Open):
+			if d, ok := m.selectedDirectory().Get(); ok {
+				return m.moveTo(d)
 			}
 			return m, nil
 
-		case tea.KeyDown:
-			m.hasChildDirectory = mo.None[bool]()
-			if m.cursor < len(m.filteredDirectories)-1 {
-				m.cursor++
+		case key.Matches(msg, m.keys.Order):
+			return m.changeOrder()
+
+		case key.Matches(msg, m.keys.Reverse):
+			return m.toggleReverse()
+
+		case key.Matches(msg, m.keys.History):
+			if m.mode == MODE_HISTORY {
+				return m.changeMode(MODE_BROWSE)
+			}
+
+			return m.changeMode(MODE_HISTORY)
+
+		case key.Matches(msg, m.keys.Bookmarks):
+			if m.mode == MODE_BOOKMARK {
+				return m.changeMode(MODE_BROWSE)
+			}
+
+			return m.changeMode(MODE_BOOKMARK)
+
+		case key.Matches(msg, m.keys.Projects):
+			if m.mode == MODE_PROJECTS {
+				return m.changeMode(MODE_BROWSE)
 			}
+
+			return m.changeMode(MODE_PROJECTS)
+
+		case key.Matches(msg, m.keys.Recursive):
+			return m.toggleRecursive()
+
+		case key.Matches(msg, m.keys.FullPath):
+			m.fullPath = !m.fullPath
+			m = m.filter()
+			m.cursor = 0
 			return m, nil
 
-		case tea.KeyLeft:
-			m.hasChildDirectory = mo.None[bool]()
-			m.textInput.SetValue("")
-			m.currentDirectory.Parent().ForEach(func(value Directory) {
-				currentPath := m.currentDirectory.String()
-				m.currentDirectory = value
-				m.directories = m.currentDirectory.Dirs(m.showAll, m.order).Map(func(value []Directory) ([]Directory, error) {
-					return value, nil
-				}).MapErr(func(err error) ([]Directory, error) {
-					m.err = err
-					return []Directory{}, err
-				}).OrElse([]Directory{})
-				m.filteredDirectories = m.directories
-
-				for i, d := range m.filteredDirectories {
-					if d.String() == currentPath {
-						m.cursor = i
-						break
-					}
-					m.cursor = 0
-				}
-			})
+		case key.Matches(msg, m.keys.SearchMode):
+			m.searchMode = m.searchMode.Next()
+			m.textInput.Prompt = m.searchMode.Prompt()
+			m = m.filter()
+			m.cursor = 0
 			return m, nil
 
-		case tea.KeyRight:
-			return m.moveTo(m.filteredDirectories[m.cursor])
+		case key.Matches(msg, m.keys.Ignored):
+			return m.toggleIgnored()
 
-		case tea.KeyShiftDown:
-			return m.changeOrder()
+		case key.Matches(msg, m.keys.Preview):
+			m.showPreview = !m.showPreview
+			return m, nil
+
+		case key.Matches(msg, m.keys.Help):
+			m.showHelp = true
+			return m, nil
+
+		case key.Matches(msg, m.keys.Cancel):
+			if m.loading() {
+				return m.cancel(), nil
+			}
+
+		case key.Matches(msg, m.keys.Complete) && m.pathQuery().IsPresent():
+			m.textInput.SetValue(m.pathQuery().MustGet().Complete(m.filteredDirectories))
+			m.textInput.CursorEnd()
+			m = m.filter()
+			m.cursor = 0
+			return m, nil
+
+		case key.Matches(msg, m.keys.Toggle) && m.multi:
+			m.selectedDirectory().ForEach(func(d Directory) {
+				m.marked = toggleMarked(m.marked, d, m.source())
+			})
+			return m.moveCursor(1), nil
+
+		case key.Matches(msg, m.keys.Select) && m.pathQuery().IsPresent():
+			return m.jump(m.pathQuery().MustGet())
 
-		case tea.KeyEnter:
+		case key.Matches(msg, m.keys.Select):
 			if len(m.filteredDirectories) == 0 {
 				return m, nil
-			} else {
-				fmt.Println(m.filteredDirectories[m.cursor].String())
 			}
 
+			m.selected = m.selectedDirectory()
 			return m, tea.Quit
 		}
 	}
@@ -236,7 +1112,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	ct := m.textInput.Value()
 	m.textInput, cmd = m.textInput.Update(msg)
-	m.filteredDirectories = filterDirectories(m.directories, m.textInput.Value())
+	m = m.filter()
 
 	if ct != m.textInput.Value() {
 		m.cursor = 0
@@ -245,42 +1121,262 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func initialModel(query string, showAll bool, displayIcons bool) model {
+func initialModel(options Options) model {
 	wd, err := os.Getwd()
 
 	if err != nil {
 		panic(err)
 	}
 
-	currentDirectory := NewDirectory(wd)
-	directories := currentDirectory.Dirs(showAll, ORDER_NAME).MapErr(func(err error) ([]Directory, error) {
+	history := NewHistory("")
+	historyPath().ForEach(func(path string) {
+		history = LoadHistory(path).MapErr(func(err error) (History, error) {
+			slog.Error(err.Error())
+			// Keep the broken file untouched rather than overwriting it on the next save.
+			return NewHistory(""), nil
+		}).OrElse(NewHistory(""))
+	})
+
+	bookmarks := loadBookmarks().MapErr(func(err error) (Bookmarks, error) {
 		slog.Error(err.Error())
-		return []Directory{}, nil
-	}).OrElse([]Directory{})
+		return NewBookmarks(""), nil
+	}).OrElse(NewBookmarks(""))
+
+	watcher := NewWatcher().MapErr(func(err error) (*Watcher, error) {
+		slog.Error(err.Error())
+		return nil, nil
+	}).OrElse(nil)
+
+	mode := MODE_BROWSE
+	if options.ShowHistory {
+		mode = MODE_HISTORY
+	}
+
+	roots := projectRoots(options.Projects.Roots)
+	var projects []Project
+	if options.ShowProjects {
+		mode = MODE_PROJECTS
+		projects = loadProjectCache().Projects(roots, options.Projects.Depth)
+	}
 
 	ti := textinput.New()
 	ti.Placeholder = "Search"
-	ti.Focus()
-	ti.Prompt = "❯ "
+
+	// A modal keymap starts out navigating.
+	if !options.KeyMap.Modal() {
+		ti.Focus()
+	}
+	ti.Prompt = options.SearchMode.Prompt()
 	ti.PromptStyle = styles.Prompt
 	ti.TextStyle = styles.Foreground
 
-	if query != "" {
-		ti.SetValue(query)
+	if options.Query != "" {
+		ti.SetValue(options.Query)
+	}
+
+	m := model{
+		currentDirectory:  NewDirectory(wd),
+		hasChildDirectory: mo.None[bool](),
+		cursor:            0,
+		textInput:         ti,
+		showAll:           options.ShowAll,
+		displayIcons:      options.DisplayIcons,
+		order:             options.Order,
+		reverse:           options.Reverse,
+		fullPath:          options.FullPath,
+		searchMode:        options.SearchMode,
+		respectIgnore:     options.RespectIgnore,
+		sizes:             map[string]int64{},
+		gitStatuses:       map[string]mo.Option[GitStatus]{},
+		mode:              mode,
+		history:           history,
+		bookmarks:         bookmarks,
+		projectRoots:      roots,
+		projectDepth:      options.Projects.Depth,
+		projects:          projects,
+		scanning:          options.ShowProjects,
+		recursive:         options.Recursive,
+		depth:             options.Depth,
+		spinner:           spinner.New(spinner.WithSpinner(spinner.MiniDot), spinner.WithStyle(styles.Count)),
+		watcher:           watcher,
+		showPreview:       options.ShowPreview,
+		previews:          map[string]Preview{},
+		layout:            options.Layout,
+		maxHeight:         options.Height,
+		listings:          map[string]Listing{},
+		keys:              options.KeyMap,
+		multi:             options.Multi,
+		printer:           options.Printer,
+		insert:            !options.KeyMap.Modal(),
+		help:              help.New(),
+		selected:          mo.None[Directory](),
+		err:               nil,
+	}
+
+	// The current directory and the history are listed in the background once the picker starts, see Init.
+	if !m.listsInBackground() {
+		m.directories = m.listDirectories().MapErr(func(err error) ([]Directory, error) {
+			slog.Error(err.Error())
+			return []Directory{}, nil
+		}).OrElse([]Directory{})
+		m.filteredDirectories = m.directories
+	}
+	m = m.watch()
+
+	if m.recursive && m.mode == MODE_BROWSE {
+		m = m.startWalk()
+	}
+
+	return m
+}
+
+// listsInBackground reports whether the directories are listed once the picker has started rather than before,
+// as listing them reads the file system.
+func (m model) listsInBackground() bool {
+	return m.mode == MODE_HISTORY || m.mode == MODE_BROWSE && !m.recursive
+}
+
+// collect lists the current directory or the history and waits for the recursive walk, the project scan and the directory sizes
+// so that every directory is listed in order without starting the picker.
+func (m model) collect() model {
+	if m.listsInBackground() {
+		m.directories = m.listDirectories().MapErr(func(err error) ([]Directory, error) {
+			m.err = err
+			return []Directory{}, nil
+		}).OrElse([]Directory{})
+	}
+
+	if m.walk != nil {
+		for directories := range m.walk {
+			m.directories = append(m.directories, directories...)
+		}
+
+		m.walkCancel()
+		m.walk, m.walkCancel = nil, nil
+	}
+
+	if m.scanning {
+		cache, projects := loadProjectCache().Scan(m.projectRoots, m.projectDepth)
+		if err := cache.Save(); err != nil {
+			m.err = err
+		}
+
+		m.scanning = false
+		m.projects = projects
+		m.directories = projectDirectories(projects)
+	}
+
+	if m.order == ORDER_SIZE && m.mode == MODE_BROWSE && !m.recursive {
+		for _, d := range m.directories {
+			m.sizes[d.String()] = d.Size(context.Background()).OrElse(0)
+		}
+
+		m.directories = m.arrange(m.directories)
+	}
+
+	m = m.filter()
+	return m
+}
+
+// filter prints every directory matching query to w without starting the picker.
+func filter(w io.Writer, m model, query string) error {
+	matcher, err := NewMatcher(query, m.fullPath, m.searchMode).Get()
+
+	if err != nil {
+		return err
+	}
+
+	directories := matcher.Filter(m.directories)
+
+	for _, d := range directories {
+		if err := m.printer.Print(w, d, m.source()); err != nil {
+			return err
+		}
+	}
+
+	if len(directories) == 0 {
+		return cli.Exit("", EXIT_CANCELLED)
 	}
 
-	return model{
-		currentDirectory:    currentDirectory,
-		hasChildDirectory:   mo.None[bool](),
-		cursor:              0,
-		directories:         directories,
-		filteredDirectories: directories,
-		textInput:           ti,
-		showAll:             showAll,
-		displayIcons:        displayIcons,
-		order:               ORDER_NAME,
-		err:                 nil,
+	return nil
+}
+
+// exit prints the selected directories to w and reports through the exit code whether any was selected.
+// The directories marked in multi-select mode are printed if there are any, or else the one under the cursor.
+func exit(w io.Writer, m model, printOnCancel bool) error {
+	if d, ok := m.selected.Get(); ok {
+		marks := []mark{{directory: d, source: m.source()}}
+		if len(m.marked) > 0 {
+			marks = m.marked
+		}
+
+		history := m.history
+		for _, mk := range marks {
+			history = history.Add(mk.directory.String(), time.Now())
+		}
+
+		if err := history.Save(); err != nil {
+			slog.Error(err.Error())
+		}
+
+		for _, mk := range marks {
+			if err := m.printer.Print(w, mk.directory, mk.source); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if printOnCancel {
+		wd, err := os.Getwd()
+
+		if err != nil {
+			return err
+		}
+
+		if err := m.printer.Print(w, NewDirectory(wd), "cancel"); err != nil {
+			return err
+		}
+	}
+
+	return cli.Exit("", EXIT_CANCELLED)
+}
+
+// source names how the directories listed were reached, for the output.
+func (m model) source() string {
+	switch m.mode {
+	case MODE_HISTORY:
+		return "history"
+	case MODE_BOOKMARK:
+		return "bookmarks"
+	case MODE_PROJECTS:
+		return "projects"
+	}
+
+	if m.recursive {
+		return "recursive"
+	}
+
+	return "browse"
+}
+
+// mark is a directory marked in multi-select mode, with the source it was reached from.
+type mark struct {
+	directory Directory
+	source    string
+}
+
+// toggleMarked marks d in multi-select mode, or unmarks it if it already is.
+func toggleMarked(marked []mark, d Directory, source string) []mark {
+	i := slices.IndexFunc(marked, func(mk mark) bool {
+		return mk.directory.String() == d.String()
+	})
+
+	if i < 0 {
+		return append(slices.Clone(marked), mark{directory: d, source: source})
 	}
+
+	return slices.Delete(slices.Clone(marked), i, i+1)
 }
 
 func main() {
@@ -291,11 +1387,14 @@ func main() {
 	}
 
 	cli.AppHelpTemplate = `USAGE:
-   {{.HelpName}} {{if .VisibleFlags}}[options]{{end}}
+   {{.HelpName}} {{if .VisibleFlags}}[options]{{end}}{{if .VisibleCommands}} [command]{{end}}
    {{if len .Authors}}
 AUTHOR:
    {{range .Authors}}{{ . }}{{end}}
-   {{end}}{{if .Commands}}
+   {{end}}{{if .VisibleCommands}}
+COMMANDS:
+   {{range .VisibleCommands}}{{join .Names ", "}}{{"\t"}}{{.Usage}}
+   {{end}}{{end}}{{if .Commands}}
 OPTIONS:
    {{range .VisibleFlags}}{{.}}
    {{end}}{{end}}{{if .Copyright }}
@@ -306,41 +1405,303 @@ VERSION:
    {{.Version}}
 {{end}}`
 
+	config := loadConfig()
+	effective := config.OrElse(DefaultConfig()).Effective()
+
 	app := &cli.App{
 		Name:    "arrow",
 		Version: "v0.1.0",
-		Flags: []cli.Flag{
-			&cli.BoolFlag{
-				Name:    "all",
-				Aliases: []string{"a"},
-				Usage:   "Show hidden files.",
-			},
-			&cli.BoolFlag{
-				Name:    "icons",
-				Aliases: []string{"i"},
-				Usage:   "Display icons.",
-			},
-			&cli.StringFlag{
-				Name:    "query",
-				Aliases: []string{"q"},
-				Usage:   "Specifies a query to search the directory.",
-			},
+		Flags:   pickerFlags(effective.Defaults),
+		Commands: []*cli.Command{
+			bookmarkCommand(),
+			initCommand(),
+			configCommand(config),
+			projectsCommand(config, pickerFlags(effective.Defaults)),
 		},
 		Action: func(ctx *cli.Context) error {
-			zone.NewGlobal()
-			output := termenv.NewOutput(os.Stderr)
-			lipgloss.SetColorProfile(output.ColorProfile())
-			p := tea.NewProgram(initialModel(ctx.String("query"), ctx.Bool("all"), ctx.Bool("icons")), tea.WithOutput(os.Stderr), tea.WithAltScreen(), tea.WithMouseCellMotion())
-			if _, err := p.Run(); err != nil {
-				fmt.Printf("error: %v", err)
-				return err
-			}
-			return nil
+			return start(ctx, config, effective.Projects, false)
 		},
 	}
 
 	if err := app.Run(os.Args); err != nil {
-		log.Fatal(err)
+		log.Println(err)
+		os.Exit(EXIT_ERROR)
+	}
+	os.Exit(EXIT_SELECTED)
+}
+
+// pickerFlags are the options of the picker, which arrow projects takes as well.
+func pickerFlags(defaults Defaults) []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "all",
+			Aliases: []string{"a"},
+			Value:   defaults.All,
+			Usage:   "Show hidden files.",
+		},
+		&cli.BoolFlag{
+			Name:    "icons",
+			Aliases: []string{"i"},
+			Value:   defaults.Icons,
+			Usage:   "Display icons.",
+		},
+		&cli.StringFlag{
+			Name:  "order",
+			Value: defaults.Order,
+			Usage: "Order of directories: name, time, natural, nocase, entries, size or frecency.",
+		},
+		&cli.BoolFlag{
+			Name:  "reverse",
+			Value: defaults.Reverse,
+			Usage: "Reverse the order of directories.",
+		},
+		&cli.BoolFlag{
+			Name:    "history",
+			Aliases: []string{"H"},
+			Usage:   "Start with the frecency-ranked list of visited directories.",
+		},
+		&cli.BoolFlag{
+			Name:    "recursive",
+			Aliases: []string{"r"},
+			Usage:   "Search the whole directory tree below the working directory.",
+		},
+		&cli.IntFlag{
+			Name:    "depth",
+			Aliases: []string{"d"},
+			Value:   5,
+			Usage:   "Maximum depth of the recursive search, 0 for no limit.",
+		},
+		&cli.BoolFlag{
+			Name:    "preview",
+			Aliases: []string{"p"},
+			Usage:   "Show the contents of the directory under the cursor.",
+		},
+		&cli.StringFlag{
+			Name:  "layout",
+			Value: defaults.Layout,
+			Usage: "Layout of the picker: default, columns or reverse.",
+		},
+		&cli.StringFlag{
+			Name:  "height",
+			Value: defaults.Height,
+			Usage: "Render the picker below the prompt in N lines or N% of the terminal instead of on the whole screen.",
+		},
+		&cli.StringFlag{
+			Name:  "keymap",
+			Value: defaults.Keymap,
+			Usage: "Key bindings: default, vim or emacs.",
+		},
+		&cli.StringFlag{
+			Name:  "theme",
+			Value: defaults.Theme,
+			Usage: "Color theme: default, dracula, nord, solarized, gruvbox, or a -dark or -light variant of the last two.",
+		},
+		&cli.BoolFlag{
+			Name:  "print-on-cancel",
+			Usage: "Print the working directory when cancelled.",
+		},
+		&cli.BoolFlag{
+			Name:  "respect-ignore",
+			Value: defaults.RespectIgnore,
+			Usage: "Hide directories ignored by .gitignore, .ignore and .arrowignore files.",
+		},
+		&cli.BoolFlag{
+			Name:  "full-path",
+			Usage: "Match the query against the full path instead of the directory name.",
+		},
+		&cli.StringFlag{
+			Name:  "search",
+			Value: SEARCH_FUZZY.String(),
+			Usage: "How the query matches: fuzzy, regex or glob.",
+		},
+		&cli.StringFlag{
+			Name:    "query",
+			Aliases: []string{"q"},
+			Usage:   "Specifies a query to search the directory.",
+		},
+		&cli.StringFlag{
+			Name:    "filter",
+			Aliases: []string{"f"},
+			Usage:   "Print the directories matching the query without starting the picker.",
+		},
+		&cli.BoolFlag{
+			Name:    "select-1",
+			Aliases: []string{"1"},
+			Usage:   "Select the directory without starting the picker when only one matches the query.",
+		},
+		&cli.BoolFlag{
+			Name:    "multi",
+			Aliases: []string{"m"},
+			Usage:   "Select several directories with tab and print them all.",
+		},
+		&cli.BoolFlag{
+			Name:  "print0",
+			Usage: "Terminate the printed directories with a NUL character instead of a newline.",
+		},
+		&cli.StringFlag{
+			Name:  "output",
+			Value: OUTPUT_PATH.String(),
+			Usage: "How the printed directories are written: path, or json with their name, symlink target, mtime, git status and source.",
+		},
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "Go template the printed directories are written with, such as '{{.Name}}: {{.Path}}'.",
+		},
+		&cli.BoolFlag{
+			Name:    "exit-0",
+			Aliases: []string{"0"},
+			Usage:   "Exit without starting the picker when no directory matches the query.",
+		},
+	}
+}
+
+// start runs the picker with the options of the command line, in the projects mode when showProjects is set.
+func start(ctx *cli.Context, config mo.Result[Config], projects Projects, showProjects bool) error {
+	if err := config.Error(); err != nil {
+		return err
 	}
-	os.Exit(0)
+
+	layout, err := LayoutFromString(ctx.String("layout")).Get()
+
+	if err != nil {
+		return err
+	}
+
+	height, err := HeightFromString(ctx.String("height")).Get()
+
+	if err != nil {
+		return err
+	}
+
+	order, err := OrderFromString(ctx.String("order")).Get()
+
+	if err != nil {
+		return err
+	}
+
+	preset, err := PresetFromString(ctx.String("keymap")).Get()
+
+	if err != nil {
+		return err
+	}
+
+	searchMode, err := SearchModeFromString(ctx.String("search")).Get()
+
+	if err != nil {
+		return err
+	}
+
+	theme, err := ThemeFromString(ctx.String("theme")).Get()
+
+	if err != nil {
+		return err
+	}
+
+	outputFormat, err := OutputFormatFromString(ctx.String("output")).Get()
+
+	if err != nil {
+		return err
+	}
+
+	printer, err := NewPrinter(outputFormat, ctx.String("format"), ctx.Bool("print0")).Get()
+
+	if err != nil {
+		return err
+	}
+
+	// NO_COLOR selects the profile without colors.
+	output := termenv.NewOutput(os.Stderr)
+	profile := output.EnvColorProfile()
+	lipgloss.SetColorProfile(profile)
+
+	dark := true
+	if theme.Adaptive() && profile != termenv.Ascii {
+		dark = output.HasDarkBackground()
+	}
+
+	effective := config.OrElse(DefaultConfig()).Effective()
+	effective.Colors = config.OrElse(DefaultConfig()).ThemeColors(theme.Colors(dark))
+
+	effective.apply()
+
+	m := initialModel(Options{
+		Query:         ctx.String("query"),
+		ShowAll:       ctx.Bool("all"),
+		DisplayIcons:  ctx.Bool("icons"),
+		ShowHistory:   ctx.Bool("history"),
+		ShowProjects:  showProjects,
+		Projects:      projects,
+		Recursive:     ctx.Bool("recursive"),
+		Depth:         ctx.Int("depth"),
+		ShowPreview:   ctx.Bool("preview"),
+		Layout:        layout,
+		Height:        height,
+		Order:         order,
+		Reverse:       ctx.Bool("reverse"),
+		FullPath:      ctx.Bool("full-path"),
+		SearchMode:    searchMode,
+		RespectIgnore: ctx.Bool("respect-ignore"),
+		KeyMap:        preset.KeyMap().Override(config.OrElse(DefaultConfig()).Keys),
+		Multi:         ctx.Bool("multi"),
+		Printer:       printer,
+	})
+
+	// The watcher is closed before the exit code the error carries ends the process.
+	err = pick(ctx, m, height)
+	if m.watcher != nil {
+		m.watcher.Close()
+	}
+
+	return err
+}
+
+// pick runs the picker, or selects without it when the options allow, and prints the result.
+func pick(ctx *cli.Context, m model, height Height) error {
+	if ctx.IsSet("filter") {
+		return filter(os.Stdout, m.collect(), ctx.String("filter"))
+	}
+
+	if ctx.Bool("select-1") || ctx.Bool("exit-0") {
+		m = m.collect()
+
+		if done, err := selectWithoutPicker(os.Stdout, m, ctx.Bool("select-1"), ctx.Bool("exit-0")); done {
+			return err
+		}
+	}
+
+	zone.NewGlobal()
+	programOptions := []tea.ProgramOption{tea.WithOutput(os.Stderr)}
+
+	// The zones clicked on are only known on the alternate screen, where the picker starts at the top left corner.
+	if !height.Inline() {
+		programOptions = append(programOptions, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	}
+
+	p := tea.NewProgram(m, programOptions...)
+	result, err := p.Run()
+
+	if err != nil {
+		return err
+	}
+
+	return exit(os.Stdout, result.(model), ctx.Bool("print-on-cancel"))
+}
+
+// selectWithoutPicker exits with the collected directories when selectOne finds only one of them matching the query,
+// or exitZero none, and reports whether it did.
+func selectWithoutPicker(w io.Writer, m model, selectOne, exitZero bool) (bool, error) {
+	if m.err != nil {
+		return true, m.err
+	}
+
+	if len(m.filteredDirectories) == 1 && selectOne {
+		m.selected = m.selectedDirectory()
+		return true, exit(w, m, false)
+	}
+
+	if len(m.filteredDirectories) == 0 && exitZero {
+		return true, exit(w, m, false)
+	}
+
+	return false, nil
 }
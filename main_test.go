@@ -0,0 +1,460 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/samber/mo"
+	"github.com/urfave/cli/v2"
+)
+
+func newTestModel(dir string) model {
+	return model{
+		currentDirectory:  NewDirectory(dir),
+		hasChildDirectory: mo.None[bool](),
+		textInput:         textinput.New(),
+		sizes:             map[string]int64{},
+		gitStatuses:       map[string]mo.Option[GitStatus]{},
+		previews:          map[string]Preview{},
+		listings:          map[string]Listing{},
+		keys:              DefaultKeyMap(),
+		history:           NewHistory(""),
+		insert:            true,
+		selected:          mo.None[Directory](),
+	}
+}
+
+func names(directories []Directory) []string {
+	names := []string{}
+	for _, d := range directories {
+		names = append(names, d.Name())
+	}
+
+	return names
+}
+
+func TestModelLoaded(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"a", "b", "c", "empty"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	children := []Directory{NewDirectory(filepath.Join(root, "a")), NewDirectory(filepath.Join(root, "b")), NewDirectory(filepath.Join(root, "c"))}
+	tests := []struct {
+		name       string
+		request    loadRequest
+		keys       []tea.KeyMsg
+		stale      bool
+		loaded     []Directory
+		want       []string
+		wantDir    string
+		wantCursor int
+		wantEmpty  bool
+	}{
+		{
+			name:       "When the directories are loaded",
+			request:    loadRequest{directory: NewDirectory(root)},
+			loaded:     children,
+			want:       []string{"a", "b", "c"},
+			wantDir:    root,
+			wantCursor: 0,
+		},
+		{
+			name:       "When the cursor is restored on the directory it was on",
+			request:    loadRequest{directory: NewDirectory(root), cursorPath: filepath.Join(root, "c")},
+			loaded:     children,
+			want:       []string{"a", "b", "c"},
+			wantDir:    root,
+			wantCursor: 2,
+		},
+		{
+			name:      "When the requested directory has no child directories",
+			request:   loadRequest{directory: NewDirectory(filepath.Join(root, "empty")), keepIfEmpty: true},
+			loaded:    []Directory{},
+			want:      []string{},
+			wantDir:   root,
+			wantEmpty: true,
+		},
+		{
+			name:    "When the load is superseded by another one",
+			request: loadRequest{directory: NewDirectory(filepath.Join(root, "a"))},
+			stale:   true,
+			loaded:  children,
+			want:    []string{},
+			wantDir: root,
+		},
+		{
+			name:    "When the load is cancelled",
+			request: loadRequest{directory: NewDirectory(filepath.Join(root, "a"))},
+			keys:    []tea.KeyMsg{{Type: tea.KeyEsc}},
+			loaded:  children,
+			want:    []string{},
+			wantDir: root,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, _ := newTestModel(root).load(tt.request)
+			m := next.(model)
+			id := m.loadID
+
+			if tt.stale {
+				next, _ = m.load(loadRequest{directory: NewDirectory(root)})
+				m = next.(model)
+			}
+
+			for _, k := range tt.keys {
+				next, _ = m.update(k)
+				m = next.(model)
+			}
+
+			next, _ = m.update(loadedMsg{id: id, request: tt.request, directories: tt.loaded})
+			m = next.(model)
+
+			if got := names(m.filteredDirectories); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filteredDirectories = %v, want %v", got, tt.want)
+			}
+
+			if m.currentDirectory.String() != tt.wantDir {
+				t.Errorf("currentDirectory = %v, want %v", m.currentDirectory, tt.wantDir)
+			}
+
+			if m.cursor != tt.wantCursor {
+				t.Errorf("cursor = %v, want %v", m.cursor, tt.wantCursor)
+			}
+
+			if got := !m.hasChildDirectory.OrElse(true); got != tt.wantEmpty {
+				t.Errorf("hasChildDirectory = %v, want empty %v", m.hasChildDirectory, tt.wantEmpty)
+			}
+		})
+	}
+}
+
+// exitCode is the status arrow exits with when a command returns err.
+func exitCode(err error) int {
+	var exitErr cli.ExitCoder
+
+	switch {
+	case err == nil:
+		return EXIT_SELECTED
+	case errors.As(err, &exitErr):
+		return exitErr.ExitCode()
+	}
+
+	return EXIT_ERROR
+}
+
+func TestFilter(t *testing.T) {
+	m := newTestModel("/src")
+	m.directories = []Directory{NewDirectory("/src/api"), NewDirectory("/src/web"), NewDirectory("/src/apps")}
+	tests := []struct {
+		name       string
+		query      string
+		searchMode SearchMode
+		want       string
+		wantCode   int
+	}{
+		{
+			name:     "When directories match",
+			query:    "^ap",
+			want:     "/src/api\n/src/apps\n",
+			wantCode: EXIT_SELECTED,
+		},
+		{
+			name:     "When no directory matches",
+			query:    "docs",
+			want:     "",
+			wantCode: EXIT_CANCELLED,
+		},
+		{
+			name:       "When the pattern is invalid",
+			query:      "(",
+			searchMode: SEARCH_REGEX,
+			want:       "",
+			wantCode:   EXIT_ERROR,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			m.searchMode = tt.searchMode
+			err := filter(&b, m, tt.query)
+
+			if b.String() != tt.want {
+				t.Errorf("filter() printed %q, want %q", b.String(), tt.want)
+			}
+
+			if got := exitCode(err); got != tt.wantCode {
+				t.Errorf("filter() exits with %v, want %v", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestExit(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		selected      mo.Option[Directory]
+		printOnCancel bool
+		want          string
+		wantCode      int
+	}{
+		{
+			name:     "When a directory is selected",
+			selected: mo.Some(NewDirectory("/src/api")),
+			want:     "/src/api\n",
+			wantCode: EXIT_SELECTED,
+		},
+		{
+			name:     "When cancelled",
+			selected: mo.None[Directory](),
+			want:     "",
+			wantCode: EXIT_CANCELLED,
+		},
+		{
+			name:          "When cancelled with print on cancel",
+			selected:      mo.None[Directory](),
+			printOnCancel: true,
+			want:          wd + "\n",
+			wantCode:      EXIT_CANCELLED,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			m := newTestModel("/src")
+			m.selected = tt.selected
+			err := exit(&b, m, tt.printOnCancel)
+
+			if b.String() != tt.want {
+				t.Errorf("exit() printed %q, want %q", b.String(), tt.want)
+			}
+
+			if got := exitCode(err); got != tt.wantCode {
+				t.Errorf("exit() exits with %v, want %v", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestSelectWithoutPicker(t *testing.T) {
+	tests := []struct {
+		name        string
+		directories []Directory
+		selectOne   bool
+		exitZero    bool
+		want        string
+		wantDone    bool
+		wantCode    int
+	}{
+		{
+			name:        "When only one directory matches with select-1",
+			directories: []Directory{NewDirectory("/src/api")},
+			selectOne:   true,
+			want:        "/src/api\n",
+			wantDone:    true,
+			wantCode:    EXIT_SELECTED,
+		},
+		{
+			name:        "When several directories match with select-1",
+			directories: []Directory{NewDirectory("/src/api"), NewDirectory("/src/web")},
+			selectOne:   true,
+			want:        "",
+			wantDone:    false,
+			wantCode:    EXIT_SELECTED,
+		},
+		{
+			name:        "When no directory matches with exit-0",
+			directories: []Directory{},
+			exitZero:    true,
+			want:        "",
+			wantDone:    true,
+			wantCode:    EXIT_CANCELLED,
+		},
+		{
+			name:        "When no directory matches with select-1 only",
+			directories: []Directory{},
+			selectOne:   true,
+			want:        "",
+			wantDone:    false,
+			wantCode:    EXIT_SELECTED,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			m := newTestModel("/src")
+			m.directories, m.filteredDirectories = tt.directories, tt.directories
+			done, err := selectWithoutPicker(&b, m, tt.selectOne, tt.exitZero)
+
+			if done != tt.wantDone || b.String() != tt.want {
+				t.Errorf("selectWithoutPicker() = %v and printed %q, want %v and %q", done, b.String(), tt.wantDone, tt.want)
+			}
+
+			if got := exitCode(err); got != tt.wantCode {
+				t.Errorf("selectWithoutPicker() exits with %v, want %v", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestModelChangeModeToProjects(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "app"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "app", "go.mod"), []byte(""), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	roots := []Directory{NewDirectory(root)}
+	cache, _ := loadProjectCache().Scan(roots, 0)
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newTestModel(root)
+	m.projectRoots = roots
+	next, _ := m.changeMode(MODE_PROJECTS)
+
+	if got, want := names(next.(model).directories), []string{"app"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("changeMode(MODE_PROJECTS) listed %v before the scan, want %v", got, want)
+	}
+}
+
+func TestToggleMarked(t *testing.T) {
+	api, web := NewDirectory("/src/api"), NewDirectory("/src/web")
+	tests := []struct {
+		name   string
+		marked []mark
+		toggle Directory
+		want   []string
+	}{
+		{
+			name:   "When the directory is not marked",
+			marked: []mark{{directory: api, source: "history"}},
+			toggle: web,
+			want:   []string{"history:api", "browse:web"},
+		},
+		{
+			name:   "When the directory is marked",
+			marked: []mark{{directory: api, source: "history"}, {directory: web, source: "history"}},
+			toggle: api,
+			want:   []string{"history:web"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := markNames(tt.marked)
+
+			if got := markNames(toggleMarked(tt.marked, tt.toggle, "browse")); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("toggleMarked() = %v, want %v", got, tt.want)
+			}
+
+			if !reflect.DeepEqual(markNames(tt.marked), before) {
+				t.Errorf("toggleMarked() changed the marked directories to %v", markNames(tt.marked))
+			}
+		})
+	}
+}
+
+func TestExitMarked(t *testing.T) {
+	marked := []mark{
+		{directory: NewDirectory("/src/web"), source: "history"},
+		{directory: NewDirectory("/src/api"), source: "browse"},
+	}
+	tests := []struct {
+		name   string
+		marked []mark
+		format string
+		print0 bool
+		want   string
+	}{
+		{
+			name:   "When directories are marked",
+			marked: marked,
+			want:   "/src/web\n/src/api\n",
+		},
+		{
+			name:   "When directories are marked and printed with NUL",
+			marked: marked,
+			print0: true,
+			want:   "/src/web\x00/src/api\x00",
+		},
+		{
+			name:   "When directories are marked from other modes",
+			marked: marked,
+			format: "{{.Source}} {{.Path}}",
+			want:   "history /src/web\nbrowse /src/api\n",
+		},
+		{
+			name:   "When no directory is marked",
+			marked: nil,
+			format: "{{.Source}} {{.Path}}",
+			want:   "recursive /src/cursor\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			m := newTestModel("/src")
+			m.multi = true
+			m.recursive = true
+			m.marked = tt.marked
+			m.selected = mo.Some(NewDirectory("/src/cursor"))
+			m.printer = NewPrinter(OUTPUT_PATH, tt.format, tt.print0).MustGet()
+
+			if err := exit(&b, m, false); err != nil {
+				t.Fatal(err)
+			}
+
+			if b.String() != tt.want {
+				t.Errorf("exit() printed %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestModelToggle(t *testing.T) {
+	m := newTestModel("/src")
+	m.multi = true
+	m.directories = []Directory{NewDirectory("/src/api"), NewDirectory("/src/web")}
+	m.filteredDirectories = m.directories
+
+	for _, k := range []tea.KeyMsg{{Type: tea.KeyTab}, {Type: tea.KeyTab}, {Type: tea.KeyUp}, {Type: tea.KeyTab}} {
+		next, _ := m.update(k)
+		m = next.(model)
+	}
+
+	if got, want := markNames(m.marked), []string{"browse:web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("marked = %v, want %v", got, want)
+	}
+}
+
+func markNames(marked []mark) []string {
+	var got []string
+	for _, mk := range marked {
+		got = append(got, mk.source+":"+mk.directory.Name())
+	}
+	return got
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/samber/mo"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	scoreMatch       = 16
+	bonusPrefix      = 12
+	bonusBoundary    = 10
+	bonusCamelCase   = 8
+	bonusConsecutive = 10
+	penaltyGap       = 1
+)
+
+// Match is how well a query matches a directory, with the positions of the matched runes in its label.
+type Match struct {
+	Score     int
+	Positions []int
+}
+
+type SearchMode int
+
+const (
+	SEARCH_FUZZY SearchMode = iota
+	SEARCH_REGEX
+	SEARCH_GLOB
+)
+
+var searchModes = map[string]SearchMode{
+	"fuzzy": SEARCH_FUZZY,
+	"regex": SEARCH_REGEX,
+	"glob":  SEARCH_GLOB,
+}
+
+func SearchModeFromString(s string) mo.Result[SearchMode] {
+	if mode, ok := searchModes[s]; ok {
+		return mo.Ok(mode)
+	}
+
+	return mo.Errf[SearchMode]("unknown search mode %q", s)
+}
+
+func (s SearchMode) String() string {
+	for name, mode := range searchModes {
+		if mode == s {
+			return name
+		}
+	}
+
+	return ""
+}
+
+func (s SearchMode) Next() SearchMode {
+	return (s + 1) % SearchMode(len(searchModes))
+}
+
+// Prompt is the prompt of the search box, which names the search mode unless it is fuzzy.
+func (s SearchMode) Prompt() string {
+	if s == SEARCH_FUZZY {
+		return "❯ "
+	}
+
+	return s.String() + " ❯ "
+}
+
+// Matcher matches a query against the names of directories, or their full paths.
+type Matcher struct {
+	mode     SearchMode
+	query    Query
+	pattern  string
+	regexp   *regexp.Regexp
+	fullPath bool
+}
+
+// NewMatcher parses query for the search mode. Patterns without upper case letters match regardless of case.
+func NewMatcher(query string, fullPath bool, mode SearchMode) mo.Result[Matcher] {
+	m := Matcher{mode: mode, fullPath: fullPath}
+	ignoreCase := strings.ToLower(query) == query
+
+	switch mode {
+	case SEARCH_FUZZY:
+		m.query = ParseQuery(query)
+	case SEARCH_REGEX:
+		if query == "" {
+			break
+		}
+
+		re, err := regexp.Compile(query)
+		if err != nil {
+			return mo.Err[Matcher](fmt.Errorf("invalid regex: %w", err))
+		}
+
+		if ignoreCase {
+			re = regexp.MustCompile("(?i)" + query)
+		}
+		m.regexp = re
+	case SEARCH_GLOB:
+		if _, err := path.Match(query, ""); err != nil {
+			return mo.Err[Matcher](fmt.Errorf("invalid glob %q: %w", query, err))
+		}
+		m.pattern = query
+	}
+
+	return mo.Ok(m)
+}
+
+func (m Matcher) empty() bool {
+	switch m.mode {
+	case SEARCH_REGEX:
+		return m.regexp == nil
+	case SEARCH_GLOB:
+		return m.pattern == ""
+	}
+
+	return len(m.query) == 0
+}
+
+func (m Matcher) target(d Directory) string {
+	if m.fullPath {
+		return d.String()
+	}
+
+	// History is labeled with absolute paths, matched by their last element like a listing, while the relative paths
+	// of recursive results and projects are matched whole so that a query can name the directories above.
+	if filepath.IsAbs(d.Label()) {
+		return filepath.Base(d.Label())
+	}
+
+	return d.Label()
+}
+
+// Match scores d against the query. The positions are those of the runes of the label that matched, if the label shows them.
+func (m Matcher) Match(d Directory) mo.Option[Match] {
+	target := []rune(m.target(d))
+	match, ok := m.match(target).Get()
+
+	if !ok {
+		return mo.None[Match]()
+	}
+
+	// The target and the label end the same way, unless the label is unrelated to the path like the name of a bookmark.
+	label := []rune(d.Label())
+	shift := len(label) - len(target)
+	positions := []int{}
+
+	if strings.HasSuffix(string(label), string(target)) || strings.HasSuffix(string(target), string(label)) {
+		for _, p := range match.Positions {
+			if p+shift >= 0 {
+				positions = append(positions, p+shift)
+			}
+		}
+	}
+
+	return mo.Some(Match{Score: match.Score, Positions: positions})
+}
+
+func (m Matcher) match(target []rune) mo.Option[Match] {
+	switch m.mode {
+	case SEARCH_REGEX:
+		if m.empty() {
+			return mo.Some(Match{})
+		}
+
+		loc := m.regexp.FindStringIndex(string(target))
+		if loc == nil {
+			return mo.None[Match]()
+		}
+
+		// The indexes are in bytes, the positions in runes.
+		start := utf8.RuneCountInString(string(target)[:loc[0]])
+		end := start + utf8.RuneCountInString(string(target)[loc[0]:loc[1]])
+		positions := []int{}
+		for i := start; i < end; i++ {
+			positions = append(positions, i)
+		}
+
+		return mo.Some(Match{Positions: positions})
+
+	case SEARCH_GLOB:
+		pattern, text := m.pattern, string(target)
+		if strings.ToLower(pattern) == pattern {
+			text = strings.ToLower(text)
+		}
+
+		if ok, _ := path.Match(pattern, text); ok || m.empty() {
+			return mo.Some(Match{})
+		}
+
+		return mo.None[Match]()
+	}
+
+	return m.query.Match(target)
+}
+
+// Filter returns the directories matching the query. Fuzzy matches are ranked with the best first, the others keep their order.
+func (m Matcher) Filter(directories []Directory) []Directory {
+	if m.empty() {
+		return directories
+	}
+
+	type scored struct {
+		directory Directory
+		score     int
+	}
+
+	var matches []scored
+	for _, d := range directories {
+		m.Match(d).ForEach(func(match Match) {
+			matches = append(matches, scored{directory: d, score: match.Score})
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	filtered := make([]Directory, 0, len(matches))
+	for _, match := range matches {
+		filtered = append(filtered, match.directory)
+	}
+
+	return filtered
+}
+
+// fuzzyMatch finds the runes of query in order in text, trying every start and keeping the best scoring one.
+func fuzzyMatch(query []rune, text []rune) mo.Option[Match] {
+	if len(query) == 0 {
+		return mo.Some(Match{})
+	}
+
+	normalized := normalize(text)
+	best := mo.None[Match]()
+
+	for start := range normalized {
+		if normalized[start] != query[0] {
+			continue
+		}
+
+		positions := make([]int, 0, len(query))
+		qi := 0
+		for i := start; i < len(normalized) && qi < len(query); i++ {
+			if normalized[i] == query[qi] {
+				positions = append(positions, i)
+				qi++
+			}
+		}
+
+		if qi < len(query) {
+			// Later starts cannot match either.
+			break
+		}
+
+		score := scorePositions(text, positions)
+		if b, ok := best.Get(); !ok || score > b.Score {
+			best = mo.Some(Match{Score: score, Positions: positions})
+		}
+	}
+
+	return best
+}
+
+func scorePositions(text []rune, positions []int) int {
+	score := 0
+
+	for i, p := range positions {
+		score += scoreMatch
+
+		switch {
+		case p == 0:
+			score += bonusPrefix
+		case isSeparator(text[p-1]):
+			score += bonusBoundary
+		case unicode.IsLower(text[p-1]) && unicode.IsUpper(text[p]),
+			unicode.IsDigit(text[p-1]) != unicode.IsDigit(text[p]):
+			score += bonusCamelCase
+		}
+
+		if i > 0 {
+			if p == positions[i-1]+1 {
+				score += bonusConsecutive
+			} else {
+				score -= penaltyGap * (p - positions[i-1] - 1)
+			}
+		}
+	}
+
+	return score
+}
+
+func isSeparator(r rune) bool {
+	switch r {
+	case '/', '\\', '-', '_', '.', ' ':
+		return true
+	}
+
+	return false
+}
+
+// normalize folds the case and strips the accents of each rune, keeping one rune per rune so positions still line up.
+func normalize(runes []rune) []rune {
+	normalized := make([]rune, len(runes))
+
+	for i, r := range runes {
+		if decomposed := []rune(norm.NFD.String(string(r))); len(decomposed) > 0 {
+			r = decomposed[0]
+		}
+
+		normalized[i] = unicode.ToLower(r)
+	}
+
+	return normalized
+}
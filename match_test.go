@@ -0,0 +1,201 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatcherFilter(t *testing.T) {
+	directories := []Directory{
+		NewDirectory("/api/xapxixx"),
+		NewDirectory("/src/my-api"),
+		NewDirectory("/src/apps"),
+		NewDirectory("/src/api"),
+		NewDirectory("/src/Café"),
+	}
+	tests := []struct {
+		name     string
+		query    string
+		fullPath bool
+		want     []string
+	}{
+		{
+			name:     "When the query is empty",
+			query:    "",
+			fullPath: false,
+			want:     []string{"/api/xapxixx", "/src/my-api", "/src/apps", "/src/api", "/src/Café"},
+		},
+		{
+			name:     "When matching names",
+			query:    "api",
+			fullPath: false,
+			want:     []string{"/src/api", "/src/my-api", "/api/xapxixx"},
+		},
+		{
+			name:     "When matching full paths",
+			query:    "srcap",
+			fullPath: true,
+			want:     []string{"/src/apps", "/src/api", "/src/my-api"},
+		},
+		{
+			name:     "When the query has no accents",
+			query:    "cafe",
+			fullPath: false,
+			want:     []string{"/src/Café"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range NewMatcher(tt.query, tt.fullPath, SEARCH_FUZZY).MustGet().Filter(directories) {
+				got = append(got, d.String())
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Matcher.Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcherMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		fullPath  bool
+		directory Directory
+		want      []int
+	}{
+		{
+			name:      "When the match is contiguous",
+			query:     "api",
+			fullPath:  false,
+			directory: NewDirectory("/src/xapi-api"),
+			want:      []int{5, 6, 7},
+		},
+		{
+			name:      "When the label is a relative path",
+			query:     "svc/api/handl",
+			fullPath:  false,
+			directory: NewDirectory("/src/svc/api/handler").WithLabel("svc/api/handler"),
+			want:      []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12},
+		},
+		{
+			name:      "When the label is an absolute path",
+			query:     "api",
+			fullPath:  false,
+			directory: NewDirectory("/src/api").WithLabel("/src/api"),
+			want:      []int{5, 6, 7},
+		},
+		{
+			name:      "When matching the parent in full path",
+			query:     "sa",
+			fullPath:  true,
+			directory: NewDirectory("/src/api"),
+			want:      []int{0},
+		},
+		{
+			name:      "When the label is not part of the path",
+			query:     "api",
+			fullPath:  true,
+			directory: NewDirectory("/src/api").WithLabel("work"),
+			want:      []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, ok := NewMatcher(tt.query, tt.fullPath, SEARCH_FUZZY).MustGet().Match(tt.directory).Get()
+
+			if !ok {
+				t.Fatalf("Matcher.Match() = None")
+			}
+
+			if !reflect.DeepEqual(match.Positions, tt.want) {
+				t.Errorf("Matcher.Match().Positions = %v, want %v", match.Positions, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMatcher(t *testing.T) {
+	directories := []Directory{
+		NewDirectory("/src/release-2026-01"),
+		NewDirectory("/src/release-2026-x"),
+		NewDirectory("/src/Release-2025-3"),
+	}
+	tests := []struct {
+		name    string
+		query   string
+		mode    SearchMode
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "When matching a regex",
+			query: `^release-2026-\d+`,
+			mode:  SEARCH_REGEX,
+			want:  []string{"/src/release-2026-01"},
+		},
+		{
+			name:  "When matching a regex with upper case letters",
+			query: `^Release`,
+			mode:  SEARCH_REGEX,
+			want:  []string{"/src/Release-2025-3"},
+		},
+		{
+			name:  "When matching a glob",
+			query: "release-*-?",
+			mode:  SEARCH_GLOB,
+			want:  []string{"/src/release-2026-x", "/src/Release-2025-3"},
+		},
+		{
+			name:  "When the regex is empty",
+			query: "",
+			mode:  SEARCH_REGEX,
+			want:  []string{"/src/release-2026-01", "/src/release-2026-x", "/src/Release-2025-3"},
+		},
+		{
+			name:    "When the regex is invalid",
+			query:   "release-(",
+			mode:    SEARCH_REGEX,
+			wantErr: true,
+		},
+		{
+			name:    "When the glob is invalid",
+			query:   "release-[",
+			mode:    SEARCH_GLOB,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher := NewMatcher(tt.query, false, tt.mode)
+
+			if matcher.IsError() != tt.wantErr {
+				t.Fatalf("NewMatcher(%q).IsError() = %v, want %v", tt.query, matcher.IsError(), tt.wantErr)
+			}
+
+			var got []string
+			for _, d := range matcher.OrEmpty().Filter(directories) {
+				got = append(got, d.String())
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Matcher.Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchModeNext(t *testing.T) {
+	if got := SEARCH_GLOB.Next(); got != SEARCH_FUZZY {
+		t.Errorf("SEARCH_GLOB.Next() = %v, want %v", got, SEARCH_FUZZY)
+	}
+
+	if got := SEARCH_REGEX.Prompt(); got != "regex ❯ " {
+		t.Errorf("SEARCH_REGEX.Prompt() = %q, want %q", got, "regex ❯ ")
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/samber/mo"
+)
+
+// Travel is how a directory was reached, which decides how the navigation stacks change.
+type Travel int
+
+const (
+	TRAVEL_VISIT Travel = iota
+	TRAVEL_BACK
+	TRAVEL_FORWARD
+)
+
+// location is a directory left during the session, with the directory selected and the query typed in it.
+type location struct {
+	directory  Directory
+	cursorPath string
+	query      string
+}
+
+// Navigation keeps the directories to go back and forward to, like a browser.
+type Navigation struct {
+	back    []location
+	forward []location
+}
+
+func (n Navigation) Back() mo.Option[location] {
+	return top(n.back)
+}
+
+func (n Navigation) Forward() mo.Option[location] {
+	return top(n.forward)
+}
+
+// Leave records from as the directory left by travelling. Visiting a new directory drops the directories to go forward to.
+// The stacks are copied rather than appended to, as the models sharing them are values.
+func (n Navigation) Leave(from location, travel Travel) Navigation {
+	switch travel {
+	case TRAVEL_BACK:
+		if len(n.back) > 0 {
+			n.back = n.back[:len(n.back)-1]
+		}
+		n.forward = append(slices.Clone(n.forward), from)
+	case TRAVEL_FORWARD:
+		if len(n.forward) > 0 {
+			n.forward = n.forward[:len(n.forward)-1]
+		}
+		n.back = append(slices.Clone(n.back), from)
+	default:
+		n.back = append(slices.Clone(n.back), from)
+		n.forward = nil
+	}
+
+	return n
+}
+
+func top(locations []location) mo.Option[location] {
+	if len(locations) == 0 {
+		return mo.None[location]()
+	}
+
+	return mo.Some(locations[len(locations)-1])
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNavigationLeave(t *testing.T) {
+	a := location{directory: Directory{path: "/a"}, cursorPath: "/a/x", query: "x"}
+	b := location{directory: Directory{path: "/b"}}
+	c := location{directory: Directory{path: "/c"}}
+	tests := []struct {
+		name       string
+		navigation Navigation
+		from       location
+		travel     Travel
+		want       Navigation
+	}{
+		{
+			name:       "When visiting a directory",
+			navigation: Navigation{back: []location{a}, forward: []location{c}},
+			from:       b,
+			travel:     TRAVEL_VISIT,
+			want:       Navigation{back: []location{a, b}},
+		},
+		{
+			name:       "When going back",
+			navigation: Navigation{back: []location{a, b}},
+			from:       c,
+			travel:     TRAVEL_BACK,
+			want:       Navigation{back: []location{a}, forward: []location{c}},
+		},
+		{
+			name:       "When going forward",
+			navigation: Navigation{back: []location{a}, forward: []location{c}},
+			from:       b,
+			travel:     TRAVEL_FORWARD,
+			want:       Navigation{back: []location{a, b}, forward: []location{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.navigation.Leave(tt.from, tt.travel); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Leave() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNavigationBack(t *testing.T) {
+	a := location{directory: Directory{path: "/a"}, cursorPath: "/a/x", query: "x"}
+	navigation := Navigation{}.Leave(a, TRAVEL_VISIT)
+
+	if got, ok := navigation.Back().Get(); !ok || !reflect.DeepEqual(got, a) {
+		t.Errorf("Back() = %v, want %v", got, a)
+	}
+
+	if navigation.Forward().IsPresent() {
+		t.Errorf("Forward() = %v, want none", navigation.Forward())
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/samber/mo"
+)
+
+type OutputFormat int
+
+const (
+	OUTPUT_PATH OutputFormat = iota
+	OUTPUT_JSON
+)
+
+var outputFormats = map[string]OutputFormat{
+	"path": OUTPUT_PATH,
+	"json": OUTPUT_JSON,
+}
+
+func OutputFormatFromString(s string) mo.Result[OutputFormat] {
+	if format, ok := outputFormats[s]; ok {
+		return mo.Ok(format)
+	}
+
+	return mo.Errf[OutputFormat]("unknown output format %q", s)
+}
+
+func (o OutputFormat) String() string {
+	for name, format := range outputFormats {
+		if format == o {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// Result describes a printed directory for the format template, which only looks into it as far as the template asks.
+type Result struct {
+	directory Directory
+	source    string
+}
+
+func NewResult(d Directory, source string) Result {
+	return Result{directory: d, source: source}
+}
+
+func (r Result) Path() string {
+	return r.directory.String()
+}
+
+func (r Result) Name() string {
+	return r.directory.Name()
+}
+
+func (r Result) Symlink() string {
+	return r.directory.SymLink().OrEmpty()
+}
+
+// ModTime is when the directory was last modified, or nil if it cannot be read.
+func (r Result) ModTime() *time.Time {
+	info, err := os.Stat(r.directory.String())
+	if err != nil {
+		return nil
+	}
+
+	modTime := info.ModTime()
+	return &modTime
+}
+
+// Git is the state of the repository at the directory, or nil if it is not one.
+func (r Result) Git() *GitStatus {
+	status, ok := ReadGitStatus(r.directory).Get()
+	if !ok {
+		return nil
+	}
+
+	return &status
+}
+
+// Source is how the directory was reached: browse, recursive, history, bookmarks, projects or cancel.
+func (r Result) Source() string {
+	return r.source
+}
+
+type resultJSON struct {
+	Path    string     `json:"path"`
+	Name    string     `json:"name"`
+	Symlink string     `json:"symlink,omitempty"`
+	ModTime *time.Time `json:"mtime,omitempty"`
+	Git     *GitStatus `json:"git,omitempty"`
+	Source  string     `json:"source"`
+}
+
+func (r Result) MarshalJSON() ([]byte, error) {
+	return json.Marshal(resultJSON{
+		Path:    r.Path(),
+		Name:    r.Name(),
+		Symlink: r.Symlink(),
+		ModTime: r.ModTime(),
+		Git:     r.Git(),
+		Source:  r.Source(),
+	})
+}
+
+// Printer writes the selected directories as paths, JSON or with a template, each one terminated by a newline or a NUL character.
+type Printer struct {
+	format   OutputFormat
+	template *template.Template
+	print0   bool
+}
+
+func NewPrinter(format OutputFormat, tmpl string, print0 bool) mo.Result[Printer] {
+	p := Printer{format: format, print0: print0}
+
+	if tmpl == "" {
+		return mo.Ok(p)
+	}
+
+	if format != OUTPUT_PATH {
+		return mo.Err[Printer](errors.New("--format cannot be combined with --output " + format.String()))
+	}
+
+	t, err := template.New("format").Parse(tmpl)
+	if err != nil {
+		return mo.Err[Printer](fmt.Errorf("invalid format: %w", err))
+	}
+	p.template = t
+
+	return mo.Ok(p)
+}
+
+// Print writes d, which is only looked into further than its path as far as the output needs.
+func (p Printer) Print(w io.Writer, d Directory, source string) error {
+	terminator := "\n"
+	if p.print0 {
+		terminator = "\x00"
+	}
+
+	switch {
+	case p.template != nil:
+		var b strings.Builder
+		if err := p.template.Execute(&b, NewResult(d, source)); err != nil {
+			return err
+		}
+
+		_, err := io.WriteString(w, b.String()+terminator)
+		return err
+
+	case p.format == OUTPUT_JSON:
+		data, err := json.Marshal(NewResult(d, source))
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(w, string(data)+terminator)
+		return err
+	}
+
+	_, err := io.WriteString(w, d.String()+terminator)
+	return err
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewPrinter(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  OutputFormat
+		tmpl    string
+		wantErr bool
+	}{
+		{name: "When printing paths", format: OUTPUT_PATH, tmpl: "", wantErr: false},
+		{name: "When printing with a template", format: OUTPUT_PATH, tmpl: "{{.Name}}", wantErr: false},
+		{name: "When the template is invalid", format: OUTPUT_PATH, tmpl: "{{.Name", wantErr: true},
+		{name: "When a template is combined with JSON", format: OUTPUT_JSON, tmpl: "{{.Name}}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewPrinter(tt.format, tt.tmpl, false); got.IsError() != tt.wantErr {
+				t.Errorf("NewPrinter() error = %v, wantErr %v", got.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrinterPrint(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "svc"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, "svc"), filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewDirectory(filepath.Join(root, "svc"))
+	link := NewDirectory(filepath.Join(root, "link"))
+	tests := []struct {
+		name      string
+		format    OutputFormat
+		tmpl      string
+		print0    bool
+		directory Directory
+		want      string
+	}{
+		{
+			name:      "When printing a path",
+			format:    OUTPUT_PATH,
+			directory: svc,
+			want:      svc.String() + "\n",
+		},
+		{
+			name:      "When printing a path terminated by NUL",
+			format:    OUTPUT_PATH,
+			print0:    true,
+			directory: svc,
+			want:      svc.String() + "\x00",
+		},
+		{
+			name:      "When printing with a template",
+			format:    OUTPUT_PATH,
+			tmpl:      "{{.Source}}:{{.Name}}:{{.Symlink}}",
+			directory: link,
+			want:      "history:link:" + svc.String() + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			printer := NewPrinter(tt.format, tt.tmpl, tt.print0).MustGet()
+
+			if err := printer.Print(&b, tt.directory, "history"); err != nil {
+				t.Fatal(err)
+			}
+
+			if b.String() != tt.want {
+				t.Errorf("Print() = %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+
+	t.Run("When printing JSON", func(t *testing.T) {
+		var b strings.Builder
+		if err := NewPrinter(OUTPUT_JSON, "", false).MustGet().Print(&b, link, "bookmarks"); err != nil {
+			t.Fatal(err)
+		}
+
+		var got resultJSON
+		if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Path != link.String() || got.Name != "link" || got.Symlink != svc.String() || got.Source != "bookmarks" || got.ModTime == nil || got.Git != nil {
+			t.Errorf("Print() = %+v", got)
+		}
+	})
+
+	t.Run("When the directory no longer exists", func(t *testing.T) {
+		var b strings.Builder
+		if err := NewPrinter(OUTPUT_JSON, "", false).MustGet().Print(&b, NewDirectory(filepath.Join(root, "gone")), "browse"); err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Contains(b.String(), "mtime") {
+			t.Errorf("Print() = %q, want no mtime", b.String())
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// pathPrefixes make the query a path typed into the search box instead of a pattern.
+var pathPrefixes = []string{"/", "~/", "./", "../", "-/", "$"}
+
+// IsPathQuery reports whether query is a path, which starts like one or is ~, .. or - on its own.
+func IsPathQuery(query string) bool {
+	switch query {
+	case "~", "..", "-":
+		return true
+	}
+
+	for _, prefix := range pathPrefixes {
+		if strings.HasPrefix(query, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PathQuery is a path typed into the search box, split into the directory it names and the beginning of the name
+// typed after it.
+type PathQuery struct {
+	query string
+	// prefix is the query up to the name being typed.
+	prefix  string
+	dir     string
+	partial string
+}
+
+// ParsePathQuery resolves query relative to base, expanding environment variables, ~ to the home directory
+// and - to the previous directory.
+func ParsePathQuery(query, base, previous string) PathQuery {
+	expanded := os.ExpandEnv(query)
+
+	if expanded == "-" || strings.HasPrefix(expanded, "-/") {
+		expanded = previous + expanded[1:]
+	}
+	expanded = ExpandHome(expanded)
+
+	p := PathQuery{query: query}
+	raw := query[strings.LastIndex(query, "/")+1:]
+
+	switch {
+	case raw == "~" || raw == "-" || raw == "." || raw == ".." || strings.HasPrefix(raw, "$"):
+		// The last element names a directory rather than the beginning of a name.
+		p.prefix, p.dir = query+"/", expanded
+	default:
+		slash := strings.LastIndex(expanded, "/")
+		p.prefix, p.partial = query[:len(query)-len(raw)], expanded[slash+1:]
+		p.dir = expanded[:slash+1]
+	}
+
+	if !filepath.IsAbs(p.dir) {
+		p.dir = filepath.Join(base, p.dir)
+	}
+	p.dir = filepath.Clean(p.dir)
+
+	return p
+}
+
+// Target is the directory the query names as typed.
+func (p PathQuery) Target() string {
+	return filepath.Join(p.dir, p.partial)
+}
+
+// Candidates lists the directories the name being typed can be completed to. Hidden ones are listed if the name
+// starts with a dot, and a name without upper case letters matches regardless of case.
+func (p PathQuery) Candidates() []Directory {
+	showAll := strings.HasPrefix(p.partial, ".")
+	directories := NewDirectory(p.dir).Dirs(showAll, ORDER_NAME).OrElse([]Directory{})
+	ignoreCase := strings.ToLower(p.partial) == p.partial
+
+	var candidates []Directory
+	for _, d := range directories {
+		name := d.Name()
+		if ignoreCase {
+			name = strings.ToLower(name)
+		}
+
+		if strings.HasPrefix(name, p.partial) {
+			candidates = append(candidates, d)
+		}
+	}
+
+	return candidates
+}
+
+// Complete extends the query with the part the names of candidates have in common, and a slash once only one is left.
+func (p PathQuery) Complete(candidates []Directory) string {
+	if len(candidates) == 0 {
+		return p.query
+	}
+
+	if len(candidates) == 1 {
+		return p.prefix + candidates[0].Name() + "/"
+	}
+
+	common := candidates[0].Name()
+	for _, d := range candidates[1:] {
+		common = commonPrefix(common, d.Name())
+	}
+
+	if len(common) <= len(p.partial) {
+		return p.prefix + p.partial
+	}
+
+	return p.prefix + common
+}
+
+func commonPrefix(a, b string) string {
+	ra, rb := []rune(a), []rune(b)
+	n := 0
+
+	for n < len(ra) && n < len(rb) && ra[n] == rb[n] {
+		n++
+	}
+
+	return string(ra[:n])
+}
+
+// ExpandHome replaces a leading ~ in p with the home directory.
+func ExpandHome(p string) string {
+	if p != "~" && !strings.HasPrefix(p, "~/") {
+		return p
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+
+	// Joining would drop a trailing slash, which tells a directory from the beginning of a name in a typed path.
+	return home + p[1:]
+}
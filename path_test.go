@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsPathQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{query: "/usr/lo", want: true},
+		{query: "~/src", want: true},
+		{query: "~", want: true},
+		{query: "./svc", want: true},
+		{query: "..", want: true},
+		{query: "-", want: true},
+		{query: "$GOPATH/src", want: true},
+		{query: "svc", want: false},
+		{query: "^svc", want: false},
+		{query: ".git", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := IsPathQuery(tt.query); got != tt.want {
+				t.Errorf("IsPathQuery(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathQueryComplete(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"home/src/arrow", "home/src/api", "home/Documents", "work/.config", "work/service"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("HOME", filepath.Join(root, "home"))
+	t.Setenv("WORK", filepath.Join(root, "work"))
+
+	tests := []struct {
+		name       string
+		query      string
+		wantTarget string
+		want       string
+	}{
+		{
+			name:       "When the name is completed up to a slash",
+			query:      "~/sr",
+			wantTarget: filepath.Join(root, "home/sr"),
+			want:       "~/src/",
+		},
+		{
+			name:       "When the names have a common beginning",
+			query:      "~/src/a",
+			wantTarget: filepath.Join(root, "home/src/a"),
+			want:       "~/src/a",
+		},
+		{
+			name:       "When the name is typed in lower case",
+			query:      "~/doc",
+			wantTarget: filepath.Join(root, "home/doc"),
+			want:       "~/Documents/",
+		},
+		{
+			name:       "When the path is relative to the current directory",
+			query:      "../work/s",
+			wantTarget: filepath.Join(root, "work/s"),
+			want:       "../work/service/",
+		},
+		{
+			name:       "When the path starts with an environment variable",
+			query:      "$WORK",
+			wantTarget: filepath.Join(root, "work"),
+			want:       "$WORK/service/",
+		},
+		{
+			name:       "When a hidden directory is typed",
+			query:      "$WORK/.c",
+			wantTarget: filepath.Join(root, "work/.c"),
+			want:       "$WORK/.config/",
+		},
+		{
+			name:       "When the path is the previous directory",
+			query:      "-/s",
+			wantTarget: filepath.Join(root, "work/s"),
+			want:       "-/service/",
+		},
+		{
+			name:       "When no directory matches",
+			query:      "~/x",
+			wantTarget: filepath.Join(root, "home/x"),
+			want:       "~/x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := ParsePathQuery(tt.query, filepath.Join(root, "home"), filepath.Join(root, "work"))
+
+			if got := p.Target(); got != tt.wantTarget {
+				t.Errorf("Target() = %v, want %v", got, tt.wantTarget)
+			}
+
+			if got := p.Complete(p.Candidates()); got != tt.want {
+				t.Errorf("Complete() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
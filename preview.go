@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type PreviewStyles struct {
+	Pane      lipgloss.Style
+	Directory lipgloss.Style
+	File      lipgloss.Style
+	Info      lipgloss.Style
+	Message   lipgloss.Style
+	Error     lipgloss.Style
+}
+
+var previewStyles = newPreviewStyles(colors)
+
+func newPreviewStyles(c Colors) PreviewStyles {
+	return PreviewStyles{
+		Pane:      lipgloss.DefaultRenderer().NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderLeft(true).BorderForeground(lipgloss.Color(c.Border)).PaddingLeft(1),
+		Directory: lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(c.Highlight)),
+		File:      lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(c.Foreground)),
+		Info:      lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(c.Disabled)),
+		Message:   lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color(c.Disabled)),
+		Error:     lipgloss.DefaultRenderer().NewStyle().Foreground(lipgloss.Color("9")),
+	}
+}
+
+type Preview struct {
+	entries []fs.FileInfo
+	loading bool
+	err     error
+}
+
+type previewMsg struct {
+	path    string
+	preview Preview
+}
+
+func loadPreview(d Directory, showAll bool) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := d.Entries(showAll).Get()
+		return previewMsg{path: d.String(), preview: Preview{entries: entries, err: err}}
+	}
+}
+
+func PreviewView(preview Preview, height int) string {
+	if preview.loading {
+		return previewStyles.Message.Render("Loading...")
+	}
+
+	if preview.err != nil {
+		return previewStyles.Error.Render(preview.err.Error())
+	}
+
+	if len(preview.entries) == 0 {
+		return previewStyles.Message.Render("Empty directory.")
+	}
+
+	entries := preview.entries
+	if len(entries) > height {
+		entries = entries[:height]
+	}
+
+	var lines []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			lines = append(lines, fmt.Sprintf("%s %s %s", previewStyles.Info.Render(fmt.Sprintf("%6s", "-")), previewStyles.Info.Render(entry.ModTime().Format("Jan _2 15:04")), previewStyles.Directory.Render(entry.Name()+"/")))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s %s %s", previewStyles.Info.Render(fmt.Sprintf("%6s", formatSize(entry.Size()))), previewStyles.Info.Render(entry.ModTime().Format("Jan _2 15:04")), previewStyles.File.Render(entry.Name())))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f%c", float64(size)/float64(div), "KMGTPE"[exp])
+}
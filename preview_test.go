@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{
+			size: 0,
+			want: "0B",
+		},
+		{
+			size: 1023,
+			want: "1023B",
+		},
+		{
+			size: 1536,
+			want: "1.5K",
+		},
+		{
+			size: 5 * 1024 * 1024,
+			want: "5.0M",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatSize(tt.size); got != tt.want {
+				t.Errorf("formatSize(%v) = %v, want %v", tt.size, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/samber/mo"
+	"github.com/urfave/cli/v2"
+)
+
+// projectMarkers are the files that make a directory a project, in order of precedence, so that the type of
+// a Go module in a git repository is go.mod rather than .git.
+var projectMarkers = []string{"go.mod", "Cargo.toml", "package.json", "pyproject.toml", "Gemfile", ".git"}
+
+// Project is a directory found below one of the project roots.
+type Project struct {
+	Path   string
+	Root   string
+	Marker string
+}
+
+// Directory labels the project with its path below the root, like ghq does.
+func (p Project) Directory() Directory {
+	label, err := filepath.Rel(p.Root, p.Path)
+	if err != nil {
+		label = p.Path
+	}
+
+	return NewDirectory(p.Path).WithLabel(label).WithProject(p.Marker)
+}
+
+type scanEntry struct {
+	ModTime  time.Time `json:"mod_time"`
+	Marker   string    `json:"marker,omitempty"`
+	Children []string  `json:"children,omitempty"`
+}
+
+// ProjectCache remembers what the directories below the project roots contained when they were last read,
+// so that only the directories modified since are read again.
+type ProjectCache struct {
+	path    string
+	entries map[string]scanEntry
+}
+
+func NewProjectCache(path string) ProjectCache {
+	return ProjectCache{path: path, entries: map[string]scanEntry{}}
+}
+
+func projectCachePath() mo.Result[string] {
+	return dataDir().Map(func(dir string) (string, error) {
+		return filepath.Join(dir, "projects.json"), nil
+	})
+}
+
+func LoadProjectCache(path string) mo.Result[ProjectCache] {
+	cache := NewProjectCache(path)
+	data, err := os.ReadFile(path)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return mo.Ok(cache)
+	}
+
+	if err != nil {
+		return mo.Err[ProjectCache](err)
+	}
+
+	if err := json.Unmarshal(data, &cache.entries); err != nil {
+		return mo.Err[ProjectCache](err)
+	}
+
+	return mo.Ok(cache)
+}
+
+func (c ProjectCache) Save() error {
+	if c.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(c.entries)
+
+	if err != nil {
+		return err
+	}
+
+	return writeFile(c.path, data)
+}
+
+// Projects lists the projects the cache knows of below roots without reading the file system.
+func (c ProjectCache) Projects(roots []Directory, depth int) []Project {
+	var projects []Project
+
+	for _, root := range roots {
+		c.visit(root, ".", 0, depth, func(dir string, entry scanEntry) {
+			projects = append(projects, Project{Path: dir, Root: root.String(), Marker: entry.Marker})
+		})
+	}
+
+	return projects
+}
+
+// Scan revalidates the cache against the directories below roots, down to depth, and returns it with the projects found.
+// Directories whose modification time has not changed are not read again.
+func (c ProjectCache) Scan(roots []Directory, depth int) (ProjectCache, []Project) {
+	next := ProjectCache{path: c.path, entries: map[string]scanEntry{}}
+	var projects []Project
+
+	for _, root := range roots {
+		c.scan(root, ".", 0, depth, next.entries, func(dir string, entry scanEntry) {
+			projects = append(projects, Project{Path: dir, Root: root.String(), Marker: entry.Marker})
+		})
+	}
+
+	return next, projects
+}
+
+func (c ProjectCache) visit(root Directory, name string, level, depth int, found func(string, scanEntry)) {
+	dir := filepath.Join(root.String(), name)
+	entry, ok := c.entries[dir]
+
+	if !ok {
+		return
+	}
+
+	// The roots themselves are searched rather than listed, even if they are a project like a repository of dotfiles.
+	if entry.Marker != "" && level > 0 {
+		found(dir, entry)
+		return
+	}
+
+	if depth > 0 && level >= depth {
+		return
+	}
+
+	for _, child := range entry.Children {
+		c.visit(root, path.Join(name, child), level+1, depth, found)
+	}
+}
+
+func (c ProjectCache) scan(root Directory, name string, level, depth int, next map[string]scanEntry, found func(string, scanEntry)) {
+	info, err := fs.Stat(root.fsys, name)
+	if err != nil {
+		return
+	}
+
+	dir := filepath.Join(root.String(), name)
+	entry, ok := c.entries[dir]
+
+	if !ok || !entry.ModTime.Equal(info.ModTime()) {
+		if entry, err = readScanEntry(root.fsys, name, info.ModTime()); err != nil {
+			return
+		}
+	}
+	next[dir] = entry
+
+	if entry.Marker != "" && level > 0 {
+		found(dir, entry)
+		return
+	}
+
+	if depth > 0 && level >= depth {
+		return
+	}
+
+	for _, child := range entry.Children {
+		c.scan(root, path.Join(name, child), level+1, depth, next, found)
+	}
+}
+
+func readScanEntry(fsys fs.FS, name string, modTime time.Time) (scanEntry, error) {
+	files, err := fs.ReadDir(fsys, name)
+	if err != nil {
+		return scanEntry{}, err
+	}
+
+	entry := scanEntry{ModTime: modTime}
+	names := map[string]bool{}
+
+	for _, file := range files {
+		names[file.Name()] = true
+
+		if file.IsDir() && !strings.HasPrefix(file.Name(), ".") {
+			entry.Children = append(entry.Children, file.Name())
+		}
+	}
+
+	for _, marker := range projectMarkers {
+		if names[marker] {
+			entry.Marker = marker
+			break
+		}
+	}
+
+	return entry, nil
+}
+
+// projectRoots resolves the configured roots, leaving out duplicates.
+func projectRoots(roots []string) []Directory {
+	var directories []Directory
+	var seen []string
+
+	for _, root := range roots {
+		root, err := filepath.Abs(ExpandHome(root))
+		if err != nil || slices.Contains(seen, root) {
+			continue
+		}
+
+		seen = append(seen, root)
+		directories = append(directories, NewDirectory(root))
+	}
+
+	return directories
+}
+
+func loadProjectCache() ProjectCache {
+	path, err := projectCachePath().Get()
+	if err != nil {
+		return NewProjectCache("")
+	}
+
+	return LoadProjectCache(path).OrElse(NewProjectCache(path))
+}
+
+type projectsMsg struct {
+	projects []Project
+	err      error
+}
+
+// scanProjects revalidates the cache on disk in the background.
+func scanProjects(roots []Directory, depth int) tea.Cmd {
+	return func() tea.Msg {
+		cache, projects := loadProjectCache().Scan(roots, depth)
+
+		return projectsMsg{projects: projects, err: cache.Save()}
+	}
+}
+
+func projectDirectories(projects []Project) []Directory {
+	directories := make([]Directory, 0, len(projects))
+	for _, project := range projects {
+		directories = append(directories, project.Directory())
+	}
+
+	return directories
+}
+
+// projectsCommand starts the picker in the projects mode, taking the options of the picker in flags as well as its own.
+func projectsCommand(config mo.Result[Config], flags []cli.Flag) *cli.Command {
+	return &cli.Command{
+		Name:  "projects",
+		Usage: "Pick a project found below the project roots.",
+		Flags: append(flags,
+			&cli.StringSliceFlag{
+				Name:  "root",
+				Usage: "Directory to search for projects, overriding projects.roots in the configuration file.",
+			},
+			&cli.BoolFlag{
+				Name:    "list",
+				Aliases: []string{"l"},
+				Usage:   "Print the projects without starting the picker.",
+			},
+		),
+		Action: func(ctx *cli.Context) error {
+			if err := config.Error(); err != nil {
+				return err
+			}
+
+			projects := config.OrElse(DefaultConfig()).Effective().Projects
+			if ctx.IsSet("root") {
+				projects.Roots = ctx.StringSlice("root")
+			}
+
+			if !ctx.Bool("list") {
+				if err := inheritFlags(ctx); err != nil {
+					return err
+				}
+
+				return start(ctx, config, projects, true)
+			}
+
+			cache, found := loadProjectCache().Scan(projectRoots(projects.Roots), projects.Depth)
+			for _, project := range found {
+				fmt.Println(project.Path)
+			}
+
+			return cache.Save()
+		},
+	}
+}
+
+// inheritFlags applies the options of the picker given before the subcommand, as the subcommand defines them again.
+func inheritFlags(ctx *cli.Context) error {
+	parent := ctx.Lineage()[1]
+
+	for _, name := range parent.LocalFlagNames() {
+		if ctx.IsSet(name) {
+			continue
+		}
+
+		if err := ctx.Set(name, fmt.Sprint(parent.Value(name))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestProjectCacheScan(t *testing.T) {
+	fsys := fstest.MapFS{
+		".git/HEAD":                            {Data: []byte("ref: refs/heads/main\n")},
+		"github.com/harehare/arrow/go.mod":     {Data: []byte("module arrow\n")},
+		"github.com/harehare/arrow/.git":       {Mode: fs.ModeDir},
+		"github.com/harehare/arrow/cmd/x":      {Mode: fs.ModeDir},
+		"github.com/harehare/web/.git":         {Mode: fs.ModeDir},
+		"github.com/harehare/web/package.json": {Data: []byte("{}")},
+		"github.com/harehare/notes/.git":       {Mode: fs.ModeDir},
+		"github.com/harehare/empty":            {Mode: fs.ModeDir},
+		"deep/a/b/c/Cargo.toml":                {Data: []byte("")},
+		".cache/tool/go.mod":                   {Data: []byte("")},
+	}
+	tests := []struct {
+		name  string
+		depth int
+		want  []Project
+	}{
+		{
+			name:  "When searching without a depth limit",
+			depth: 0,
+			want: []Project{
+				{Path: "/src/deep/a/b/c", Root: "/src", Marker: "Cargo.toml"},
+				{Path: "/src/github.com/harehare/arrow", Root: "/src", Marker: "go.mod"},
+				{Path: "/src/github.com/harehare/notes", Root: "/src", Marker: ".git"},
+				{Path: "/src/github.com/harehare/web", Root: "/src", Marker: "package.json"},
+			},
+		},
+		{
+			name:  "When the depth is limited",
+			depth: 3,
+			want: []Project{
+				{Path: "/src/github.com/harehare/arrow", Root: "/src", Marker: "go.mod"},
+				{Path: "/src/github.com/harehare/notes", Root: "/src", Marker: ".git"},
+				{Path: "/src/github.com/harehare/web", Root: "/src", Marker: "package.json"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roots := []Directory{{path: "/src", fsys: fsys}}
+			cache, got := NewProjectCache("").Scan(roots, tt.depth)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scan() = %v, want %v", got, tt.want)
+			}
+
+			if cached := cache.Projects(roots, tt.depth); !reflect.DeepEqual(cached, tt.want) {
+				t.Errorf("Projects() = %v, want %v", cached, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectCacheRevalidate(t *testing.T) {
+	modified := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"app/go.mod": {Data: []byte("")},
+		"lib":        {Mode: fs.ModeDir, ModTime: modified},
+		"lib/go.mod": {Data: []byte("")},
+	}
+	roots := []Directory{{path: "/src", fsys: fsys}}
+	tests := []struct {
+		name  string
+		entry scanEntry
+		want  []Project
+	}{
+		{
+			name:  "When the directory has not been modified since it was cached",
+			entry: scanEntry{ModTime: modified},
+			want:  []Project{{Path: "/src/app", Root: "/src", Marker: "go.mod"}},
+		},
+		{
+			name:  "When the directory has been modified since it was cached",
+			entry: scanEntry{ModTime: modified.Add(-time.Hour)},
+			want: []Project{
+				{Path: "/src/app", Root: "/src", Marker: "go.mod"},
+				{Path: "/src/lib", Root: "/src", Marker: "go.mod"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, _ := NewProjectCache("").Scan(roots, 0)
+			cache.entries["/src/lib"] = tt.entry
+
+			if _, got := cache.Scan(roots, 0); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectDirectory(t *testing.T) {
+	d := Project{Path: "/src/github.com/harehare/arrow", Root: "/src", Marker: "go.mod"}.Directory()
+
+	if d.Label() != "github.com/harehare/arrow" || d.project != "go.mod" {
+		t.Errorf("Directory() = %v labeled %q, want the path below the root", d.project, d.Label())
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/samber/mo"
+)
+
+type termKind int
+
+const (
+	TERM_FUZZY termKind = iota
+	TERM_EXACT
+	TERM_PREFIX
+	TERM_SUFFIX
+	TERM_EQUAL
+)
+
+type term struct {
+	kind    termKind
+	inverse bool
+	text    []rune
+}
+
+// Query is a parsed search query. Terms separated by spaces must all match, and terms joined by | need only one to match.
+type Query [][]term
+
+// ParseQuery reads the syntax of fzf: 'exact, ^prefix, suffix$, ^equal$ and !inverse, which matches exactly by default.
+func ParseQuery(s string) Query {
+	var query Query
+	or := false
+
+	for _, token := range strings.Fields(s) {
+		if token == "|" {
+			or = len(query) > 0
+			continue
+		}
+
+		t := parseTerm(token)
+		if len(t.text) == 0 {
+			continue
+		}
+
+		if or {
+			query[len(query)-1] = append(query[len(query)-1], t)
+		} else {
+			query = append(query, []term{t})
+		}
+		or = false
+	}
+
+	return query
+}
+
+func parseTerm(token string) term {
+	t := term{kind: TERM_FUZZY}
+
+	if strings.HasPrefix(token, "!") {
+		t.inverse = true
+		t.kind = TERM_EXACT
+		token = token[1:]
+	}
+
+	switch {
+	case strings.HasPrefix(token, "'"):
+		t.kind = TERM_EXACT
+		token = token[1:]
+	case strings.HasPrefix(token, "^") && strings.HasSuffix(token, "$") && len(token) > 1:
+		t.kind = TERM_EQUAL
+		token = token[1 : len(token)-1]
+	case strings.HasPrefix(token, "^"):
+		t.kind = TERM_PREFIX
+		token = token[1:]
+	case strings.HasSuffix(token, "$"):
+		t.kind = TERM_SUFFIX
+		token = token[:len(token)-1]
+	}
+
+	t.text = normalize([]rune(token))
+	return t
+}
+
+// Match requires every group of the query to match text, adding up their scores and matched positions.
+func (q Query) Match(text []rune) mo.Option[Match] {
+	normalized := normalize(text)
+	result := Match{}
+
+	for _, group := range q {
+		best := mo.None[Match]()
+
+		for _, t := range group {
+			t.match(text, normalized).ForEach(func(match Match) {
+				if b, ok := best.Get(); !ok || match.Score > b.Score {
+					best = mo.Some(match)
+				}
+			})
+		}
+
+		match, ok := best.Get()
+		if !ok {
+			return mo.None[Match]()
+		}
+
+		result.Score += match.Score
+		result.Positions = append(result.Positions, match.Positions...)
+	}
+
+	slices.Sort(result.Positions)
+	result.Positions = slices.Compact(result.Positions)
+
+	return mo.Some(result)
+}
+
+func (t term) match(text, normalized []rune) mo.Option[Match] {
+	var match mo.Option[Match]
+
+	switch t.kind {
+	case TERM_FUZZY:
+		match = fuzzyMatch(t.text, text)
+	case TERM_EXACT:
+		match = exactMatch(t.text, text, normalized)
+	case TERM_PREFIX:
+		match = matchAt(t.text, text, 0, slices.Equal(normalized[:min(len(t.text), len(normalized))], t.text))
+	case TERM_SUFFIX:
+		start := len(normalized) - len(t.text)
+		match = matchAt(t.text, text, start, start >= 0 && slices.Equal(normalized[start:], t.text))
+	case TERM_EQUAL:
+		match = matchAt(t.text, text, 0, slices.Equal(normalized, t.text))
+	}
+
+	if t.inverse {
+		if match.IsPresent() {
+			return mo.None[Match]()
+		}
+
+		return mo.Some(Match{})
+	}
+
+	return match
+}
+
+// exactMatch finds query as a substring of text, keeping the best scoring occurrence.
+func exactMatch(query, text, normalized []rune) mo.Option[Match] {
+	best := mo.None[Match]()
+
+	for start := 0; start+len(query) <= len(normalized); start++ {
+		if !slices.Equal(normalized[start:start+len(query)], query) {
+			continue
+		}
+
+		match := matchAt(query, text, start, true).MustGet()
+		if b, ok := best.Get(); !ok || match.Score > b.Score {
+			best = mo.Some(match)
+		}
+	}
+
+	return best
+}
+
+func matchAt(query, text []rune, start int, ok bool) mo.Option[Match] {
+	if !ok {
+		return mo.None[Match]()
+	}
+
+	positions := make([]int, len(query))
+	for i := range query {
+		positions[i] = start + i
+	}
+
+	return mo.Some(Match{Score: scorePositions(text, positions), Positions: positions})
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  Query
+	}{
+		{
+			query: "api",
+			want:  Query{{{kind: TERM_FUZZY, text: []rune("api")}}},
+		},
+		{
+			query: "'Api ^svc old$ ^api$",
+			want: Query{
+				{{kind: TERM_EXACT, text: []rune("api")}},
+				{{kind: TERM_PREFIX, text: []rune("svc")}},
+				{{kind: TERM_SUFFIX, text: []rune("old")}},
+				{{kind: TERM_EQUAL, text: []rune("api")}},
+			},
+		},
+		{
+			query: "!-old !^tmp",
+			want: Query{
+				{{kind: TERM_EXACT, inverse: true, text: []rune("-old")}},
+				{{kind: TERM_PREFIX, inverse: true, text: []rune("tmp")}},
+			},
+		},
+		{
+			query: "^svc | ^api go",
+			want: Query{
+				{{kind: TERM_PREFIX, text: []rune("svc")}, {kind: TERM_PREFIX, text: []rune("api")}},
+				{{kind: TERM_FUZZY, text: []rune("go")}},
+			},
+		},
+		{
+			query: "| ! ^ api",
+			want:  Query{{{kind: TERM_FUZZY, text: []rune("api")}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := ParseQuery(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseQuery(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryMatch(t *testing.T) {
+	names := []string{"svc-api", "svc-api-old", "svc-web-archive", "api-svc", "svc"}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{
+			query: "svc !-old !-archive",
+			want:  []string{"svc-api", "api-svc", "svc"},
+		},
+		{
+			query: "^svc",
+			want:  []string{"svc-api", "svc-api-old", "svc-web-archive", "svc"},
+		},
+		{
+			query: "svc$",
+			want:  []string{"api-svc", "svc"},
+		},
+		{
+			query: "^svc$",
+			want:  []string{"svc"},
+		},
+		{
+			query: "'pi-o",
+			want:  []string{"svc-api-old"},
+		},
+		{
+			query: "old$ | archive$",
+			want:  []string{"svc-api-old", "svc-web-archive"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got []string
+			for _, name := range names {
+				if ParseQuery(tt.query).Match([]rune(name)).IsPresent() {
+					got = append(got, name)
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseQuery(%q).Match() = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryMatchPositions(t *testing.T) {
+	match := ParseQuery("^sv api$ !old").Match([]rune("svc-api")).MustGet()
+
+	if want := []int{0, 1, 4, 5, 6}; !reflect.DeepEqual(match.Positions, want) {
+		t.Errorf("ParseQuery().Match().Positions = %v, want %v", match.Positions, want)
+	}
+}
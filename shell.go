@@ -0,0 +1,90 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/samber/mo"
+	"github.com/urfave/cli/v2"
+)
+
+//go:embed shell/*.tmpl
+var shellTemplates embed.FS
+
+var shells = []string{"bash", "zsh", "fish", "nushell", "sh"}
+
+type ShellOptions struct {
+	Cmd    string
+	Widget bool
+	// Height is passed to arrow opened by the widget, which takes up the whole screen if it is empty.
+	Height string
+}
+
+// ShellInit renders the integration script that defines a function changing into the selected directory.
+func ShellInit(shell string, options ShellOptions) mo.Result[string] {
+	if shell == "nu" {
+		shell = "nushell"
+	}
+
+	if !slices.Contains(shells, shell) {
+		return mo.Errf[string]("unsupported shell %q, expected one of %s", shell, strings.Join(shells, ", "))
+	}
+
+	if shell == "sh" && options.Widget {
+		return mo.Errf[string]("key bindings are not supported in POSIX sh")
+	}
+
+	if err := HeightFromString(options.Height).Error(); err != nil {
+		return mo.Err[string](err)
+	}
+
+	t, err := template.ParseFS(shellTemplates, fmt.Sprintf("shell/%s.tmpl", shell))
+
+	if err != nil {
+		return mo.Err[string](err)
+	}
+
+	var script strings.Builder
+	if err := t.Execute(&script, options); err != nil {
+		return mo.Err[string](err)
+	}
+
+	return mo.Ok(script.String())
+}
+
+func initCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "init",
+		Usage:     "Print the shell integration script.",
+		ArgsUsage: "<" + strings.Join(shells, "|") + ">",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "cmd",
+				Value: "ao",
+				Usage: "Name of the function that changes into the selected directory.",
+			},
+			&cli.BoolFlag{
+				Name:  "widget",
+				Usage: "Bind Ctrl-G to open arrow from the command line.",
+			},
+			&cli.StringFlag{
+				Name:  "height",
+				Value: "40%",
+				Usage: "Height the widget opens arrow with below the command line. Leave empty to use the configured height.",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			script, err := ShellInit(ctx.Args().First(), ShellOptions{Cmd: ctx.String("cmd"), Widget: ctx.Bool("widget"), Height: ctx.String("height")}).Get()
+
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(script)
+			return nil
+		},
+	}
+}
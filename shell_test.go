@@ -0,0 +1,108 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShellInit(t *testing.T) {
+	tests := []struct {
+		name     string
+		shell    string
+		options  ShellOptions
+		contains []string
+		excludes []string
+		wantErr  bool
+	}{
+		{
+			name:     "When bash",
+			shell:    "bash",
+			options:  ShellOptions{Cmd: "ao", Widget: false},
+			contains: []string{"ao() {"},
+			excludes: []string{"bind "},
+			wantErr:  false,
+		},
+		{
+			name:     "When zsh with a widget",
+			shell:    "zsh",
+			options:  ShellOptions{Cmd: "j", Widget: true, Height: "40%"},
+			contains: []string{"j() {", "bindkey -M emacs '^G' __arrow_widget", "command arrow --height 40% < /dev/tty"},
+			wantErr:  false,
+		},
+		{
+			name:     "When bash with a widget taking up the whole screen",
+			shell:    "bash",
+			options:  ShellOptions{Cmd: "ao", Widget: true, Height: ""},
+			contains: []string{`dir="$(command arrow)"`, "bind -m emacs-standard"},
+			wantErr:  false,
+		},
+		{
+			name:     "When fish with a widget",
+			shell:    "fish",
+			options:  ShellOptions{Cmd: "ao", Widget: true, Height: "20"},
+			contains: []string{"set -l dir (command arrow --height 20)"},
+			wantErr:  false,
+		},
+		{
+			name:     "When nu with a widget",
+			shell:    "nu",
+			options:  ShellOptions{Cmd: "ao", Widget: true, Height: "40%"},
+			contains: []string{`cmd: "ao --height 40%"`},
+			wantErr:  false,
+		},
+		{
+			name:    "When the widget height is invalid",
+			shell:   "zsh",
+			options: ShellOptions{Cmd: "ao", Widget: true, Height: "tall"},
+			wantErr: true,
+		},
+		{
+			name:     "When fish",
+			shell:    "fish",
+			options:  ShellOptions{Cmd: "ao", Widget: false},
+			contains: []string{"function ao"},
+			wantErr:  false,
+		},
+		{
+			name:     "When nu",
+			shell:    "nu",
+			options:  ShellOptions{Cmd: "ao", Widget: false},
+			contains: []string{"def --env ao"},
+			wantErr:  false,
+		},
+		{
+			name:    "When sh with a widget",
+			shell:   "sh",
+			options: ShellOptions{Cmd: "ao", Widget: true},
+			wantErr: true,
+		},
+		{
+			name:    "When unsupported shell",
+			shell:   "tcsh",
+			options: ShellOptions{Cmd: "ao", Widget: false},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ShellInit(tt.shell, tt.options)
+
+			if got.IsError() != tt.wantErr {
+				t.Fatalf("ShellInit(%v).IsError() = %v, want %v", tt.shell, got.IsError(), tt.wantErr)
+			}
+
+			for _, s := range tt.contains {
+				if !strings.Contains(got.OrEmpty(), s) {
+					t.Errorf("ShellInit(%v) = %v, want to contain %v", tt.shell, got.OrEmpty(), s)
+				}
+			}
+
+			for _, s := range tt.excludes {
+				if strings.Contains(got.OrEmpty(), s) {
+					t.Errorf("ShellInit(%v) = %v, want not to contain %v", tt.shell, got.OrEmpty(), s)
+				}
+			}
+		})
+	}
+}
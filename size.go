@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"runtime"
+	"slices"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/samber/mo"
+)
+
+// sizeSemaphore bounds the number of directory trees measured at once.
+var sizeSemaphore = make(chan struct{}, runtime.NumCPU())
+
+type sizeMsg struct {
+	path string
+	size mo.Result[int64]
+}
+
+// Size adds up the sizes of the files below the directory, giving up once ctx is done. Entries that cannot be read are skipped.
+func (d Directory) Size(ctx context.Context) mo.Result[int64] {
+	var size int64
+
+	err := fs.WalkDir(d.fsys, ".", func(path string, entry fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err != nil {
+			return nil
+		}
+
+		if entry.Type().IsRegular() {
+			if info, err := entry.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return mo.Err[int64](err)
+	}
+
+	return mo.Ok(size)
+}
+
+func loadSize(ctx context.Context, d Directory) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case sizeSemaphore <- struct{}{}:
+		case <-ctx.Done():
+			return sizeMsg{path: d.String(), size: mo.Err[int64](ctx.Err())}
+		}
+		defer func() { <-sizeSemaphore }()
+
+		return sizeMsg{path: d.String(), size: d.Size(ctx)}
+	}
+}
+
+// SortBySize orders directories from the largest, leaving those whose size is not known yet at the end in name order.
+func SortBySize(directories []Directory, sizes map[string]int64) []Directory {
+	sorted := slices.Clone(directories)
+
+	size := func(d Directory) int64 {
+		if size, ok := sizes[d.String()]; ok {
+			return size
+		}
+
+		return -1
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		si, sj := size(sorted[i]), size(sorted[j])
+		if si == sj {
+			return sorted[i].String() < sorted[j].String()
+		}
+		return si > sj
+	})
+
+	return sorted
+}
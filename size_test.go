@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSize(t *testing.T) {
+	fs := fstest.MapFS{
+		"a.txt":     {Data: []byte("foo")},
+		"sub/b.txt": {Data: []byte("barbaz")},
+		"empty":     {Mode: fs.ModeDir},
+	}
+
+	if got := (Directory{path: ".", fsys: fs}).Size(context.Background()).OrEmpty(); got != 9 {
+		t.Errorf("directory.Size() = %v, want %v", got, 9)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if got := (Directory{path: ".", fsys: fs}).Size(ctx); !got.IsError() {
+		t.Errorf("directory.Size() = %v after cancelling, want an error", got.OrEmpty())
+	}
+}
+
+func TestSortBySize(t *testing.T) {
+	directories := []Directory{NewDirectory("/a"), NewDirectory("/b"), NewDirectory("/c"), NewDirectory("/d")}
+	tests := []struct {
+		name  string
+		sizes map[string]int64
+		want  []string
+	}{
+		{
+			name:  "When every size is known",
+			sizes: map[string]int64{"/a": 1, "/b": 3, "/c": 2, "/d": 3},
+			want:  []string{"/b", "/d", "/c", "/a"},
+		},
+		{
+			name:  "When some sizes are still being measured",
+			sizes: map[string]int64{"/a": 1, "/b": -1, "/d": 0},
+			want:  []string{"/a", "/d", "/b", "/c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range SortBySize(directories, tt.sizes) {
+				got = append(got, d.String())
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortBySize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
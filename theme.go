@@ -0,0 +1,118 @@
+package main
+
+import (
+	"github.com/samber/mo"
+)
+
+// Theme is a set of colors with variants for dark and light terminal backgrounds.
+type Theme struct {
+	Dark  Colors
+	Light Colors
+}
+
+var LightColors = Colors{
+	Foreground:       "235",
+	Highlight:        "25",
+	Cursor:           "57",
+	Disabled:         "246",
+	SymLink:          "30",
+	CurrentDirectory: "57",
+	Prompt:           "30",
+	Border:           "25",
+}
+
+var (
+	draculaColors = Colors{
+		Foreground:       "#F8F8F2",
+		Highlight:        "#BD93F9",
+		Cursor:           "#FF79C6",
+		Disabled:         "#6272A4",
+		SymLink:          "#8BE9FD",
+		CurrentDirectory: "#50FA7B",
+		Prompt:           "#FF79C6",
+		Border:           "#BD93F9",
+	}
+	nordColors = Colors{
+		Foreground:       "#D8DEE9",
+		Highlight:        "#88C0D0",
+		Cursor:           "#81A1C1",
+		Disabled:         "#4C566A",
+		SymLink:          "#8FBCBB",
+		CurrentDirectory: "#5E81AC",
+		Prompt:           "#A3BE8C",
+		Border:           "#88C0D0",
+	}
+	solarizedDarkColors = Colors{
+		Foreground:       "#839496",
+		Highlight:        "#268BD2",
+		Cursor:           "#D33682",
+		Disabled:         "#586E75",
+		SymLink:          "#2AA198",
+		CurrentDirectory: "#6C71C4",
+		Prompt:           "#859900",
+		Border:           "#268BD2",
+	}
+	solarizedLightColors = Colors{
+		Foreground:       "#657B83",
+		Highlight:        "#268BD2",
+		Cursor:           "#D33682",
+		Disabled:         "#93A1A1",
+		SymLink:          "#2AA198",
+		CurrentDirectory: "#6C71C4",
+		Prompt:           "#859900",
+		Border:           "#268BD2",
+	}
+	gruvboxDarkColors = Colors{
+		Foreground:       "#EBDBB2",
+		Highlight:        "#FABD2F",
+		Cursor:           "#FE8019",
+		Disabled:         "#928374",
+		SymLink:          "#8EC07C",
+		CurrentDirectory: "#83A598",
+		Prompt:           "#B8BB26",
+		Border:           "#FABD2F",
+	}
+	gruvboxLightColors = Colors{
+		Foreground:       "#3C3836",
+		Highlight:        "#B57614",
+		Cursor:           "#AF3A03",
+		Disabled:         "#928374",
+		SymLink:          "#427B58",
+		CurrentDirectory: "#076678",
+		Prompt:           "#79740E",
+		Border:           "#B57614",
+	}
+)
+
+var themes = map[string]Theme{
+	"default":         {Dark: DefaultColors, Light: LightColors},
+	"dracula":         {Dark: draculaColors, Light: draculaColors},
+	"nord":            {Dark: nordColors, Light: nordColors},
+	"solarized":       {Dark: solarizedDarkColors, Light: solarizedLightColors},
+	"solarized-dark":  {Dark: solarizedDarkColors, Light: solarizedDarkColors},
+	"solarized-light": {Dark: solarizedLightColors, Light: solarizedLightColors},
+	"gruvbox":         {Dark: gruvboxDarkColors, Light: gruvboxLightColors},
+	"gruvbox-dark":    {Dark: gruvboxDarkColors, Light: gruvboxDarkColors},
+	"gruvbox-light":   {Dark: gruvboxLightColors, Light: gruvboxLightColors},
+}
+
+func ThemeFromString(s string) mo.Result[Theme] {
+	if theme, ok := themes[s]; ok {
+		return mo.Ok(theme)
+	}
+
+	return mo.Errf[Theme]("unknown theme %q", s)
+}
+
+// Adaptive reports whether the colors of the theme depend on the background of the terminal.
+func (t Theme) Adaptive() bool {
+	return t.Dark != t.Light
+}
+
+func (t Theme) Colors(dark bool) Colors {
+	if dark {
+		return t.Dark
+	}
+
+	return t.Light
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestThemeFromString(t *testing.T) {
+	tests := []struct {
+		theme     string
+		wantDark  Colors
+		wantLight Colors
+		wantErr   bool
+	}{
+		{
+			theme:     "default",
+			wantDark:  DefaultColors,
+			wantLight: LightColors,
+			wantErr:   false,
+		},
+		{
+			theme:     "gruvbox",
+			wantDark:  gruvboxDarkColors,
+			wantLight: gruvboxLightColors,
+			wantErr:   false,
+		},
+		{
+			theme:     "solarized-light",
+			wantDark:  solarizedLightColors,
+			wantLight: solarizedLightColors,
+			wantErr:   false,
+		},
+		{
+			theme:   "monokai",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.theme, func(t *testing.T) {
+			got := ThemeFromString(tt.theme)
+
+			if got.IsError() != tt.wantErr {
+				t.Errorf("ThemeFromString(%v).IsError() = %v, want %v", tt.theme, got.IsError(), tt.wantErr)
+			}
+
+			if got.OrEmpty().Colors(true) != tt.wantDark {
+				t.Errorf("ThemeFromString(%v).Colors(true) = %v, want %v", tt.theme, got.OrEmpty().Colors(true), tt.wantDark)
+			}
+
+			if got.OrEmpty().Colors(false) != tt.wantLight {
+				t.Errorf("ThemeFromString(%v).Colors(false) = %v, want %v", tt.theme, got.OrEmpty().Colors(false), tt.wantLight)
+			}
+		})
+	}
+}
+
+func TestThemeColorsAreValid(t *testing.T) {
+	for name, theme := range themes {
+		for _, colors := range []Colors{theme.Dark, theme.Light} {
+			for _, err := range colors.Validate() {
+				t.Errorf("themes[%s]: %v", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type walkMsg struct {
+	id          int
+	directories []Directory
+	done        bool
+}
+
+type walkJob struct {
+	dir    string
+	level  int
+	ignore Ignore
+}
+
+// Walk lists the directories below root with a fixed number of workers and streams them in batches, one batch per directory read.
+// Each directory is labeled with its path relative to root. A depth of 0 walks the whole tree.
+// With respectIgnore, directories hidden by ignore files are neither listed nor walked.
+func Walk(ctx context.Context, root Directory, showAll bool, respectIgnore bool, depth int) <-chan []Directory {
+	results := make(chan []Directory)
+	jobs := make(chan walkJob)
+	found := make(chan []walkJob)
+	var wg sync.WaitGroup
+
+	read := func(job walkJob) []walkJob {
+		entries, err := fs.ReadDir(root.fsys, job.dir)
+		if err != nil {
+			return nil
+		}
+
+		ignore := job.ignore
+		if respectIgnore && job.dir != "." {
+			ignore = ignore.Add(root.fsys, job.dir, filepath.Join(root.String(), filepath.FromSlash(job.dir)))
+		}
+
+		var directories []Directory
+		var children []walkJob
+		for _, entry := range entries {
+			if !entry.IsDir() || (!showAll && strings.HasPrefix(entry.Name(), ".")) {
+				continue
+			}
+
+			rel := path.Join(job.dir, entry.Name())
+			p := filepath.Join(root.String(), filepath.FromSlash(rel))
+
+			if respectIgnore && ignore.Ignored(p) {
+				continue
+			}
+
+			directories = append(directories, NewDirectory(p).WithLabel(rel))
+
+			if depth <= 0 || job.level < depth {
+				children = append(children, walkJob{dir: rel, level: job.level + 1, ignore: ignore})
+			}
+		}
+
+		if len(directories) > 0 {
+			select {
+			case results <- directories:
+			case <-ctx.Done():
+			}
+		}
+
+		return children
+	}
+
+	for range runtime.NumCPU() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for job := range jobs {
+				select {
+				case found <- read(job):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	ignore := Ignore{}
+	if respectIgnore {
+		ignore = LoadIgnore(root)
+	}
+
+	// The directories waiting to be read are queued here rather than each given a goroutine, as a tree can have any number of them.
+	go func() {
+		defer close(jobs)
+
+		pending := []walkJob{{dir: ".", level: 1, ignore: ignore}}
+		reading := 0
+
+		for len(pending) > 0 || reading > 0 {
+			var next chan walkJob
+			var job walkJob
+			if len(pending) > 0 {
+				next, job = jobs, pending[0]
+			}
+
+			select {
+			case next <- job:
+				pending = pending[1:]
+				reading++
+			case children := <-found:
+				pending = append(pending, children...)
+				reading--
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+func waitForWalk(id int, results <-chan []Directory) tea.Cmd {
+	return func() tea.Msg {
+		directories, ok := <-results
+		return walkMsg{id: id, directories: directories, done: !ok}
+	}
+}
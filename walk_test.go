@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWalk(t *testing.T) {
+	fs := fstest.MapFS{
+		"svc/api/handler": {Mode: fs.ModeDir},
+		"svc/web":         {Mode: fs.ModeDir},
+		"svc/.cache/foo":  {Mode: fs.ModeDir},
+		"README.md":       {Data: []byte("")},
+	}
+	tests := []struct {
+		name    string
+		showAll bool
+		depth   int
+		want    []string
+	}{
+		{
+			name:    "When walking the whole tree",
+			showAll: false,
+			depth:   0,
+			want:    []string{"svc", "svc/api", "svc/api/handler", "svc/web"},
+		},
+		{
+			name:    "When depth is limited",
+			showAll: false,
+			depth:   2,
+			want:    []string{"svc", "svc/api", "svc/web"},
+		},
+		{
+			name:    "When display all directories",
+			showAll: true,
+			depth:   0,
+			want:    []string{"svc", "svc/.cache", "svc/.cache/foo", "svc/api", "svc/api/handler", "svc/web"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for directories := range Walk(context.Background(), Directory{path: "/root", fsys: fs}, tt.showAll, false, tt.depth) {
+				for _, d := range directories {
+					got = append(got, d.Label())
+				}
+			}
+			sort.Strings(got)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Walk() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalkCancel(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for i := range 1000 {
+		fsys[fmt.Sprintf("wide/%d/child", i)] = &fstest.MapFile{Mode: fs.ModeDir}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := Walk(ctx, Directory{path: "/root", fsys: fsys}, false, false, 0)
+	<-results
+	cancel()
+
+	// The results are closed once every worker has given up.
+	for range results {
+	}
+}
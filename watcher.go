@@ -0,0 +1,95 @@
+package main
+
+import (
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+	"github.com/samber/mo"
+)
+
+// Changes arriving within this interval are reported together, so a burst like `git worktree add` reloads once.
+const watchDebounce = 100 * time.Millisecond
+
+type watchMsg struct {
+	path string
+	err  error
+}
+
+// Watcher reports directories being created, removed or renamed in the watched directory.
+type Watcher struct {
+	watcher *fsnotify.Watcher
+	path    string
+}
+
+func NewWatcher() mo.Result[*Watcher] {
+	watcher, err := fsnotify.NewWatcher()
+
+	if err != nil {
+		return mo.Err[*Watcher](err)
+	}
+
+	return mo.Ok(&Watcher{watcher: watcher})
+}
+
+// Watch replaces the watched directory with path.
+func (w *Watcher) Watch(path string) error {
+	if w.path == path {
+		return nil
+	}
+
+	if w.path != "" {
+		// The directory may already be gone, in which case it is no longer watched anyway.
+		_ = w.watcher.Remove(w.path)
+		w.path = ""
+	}
+
+	if err := w.watcher.Add(path); err != nil {
+		return err
+	}
+
+	w.path = path
+	return nil
+}
+
+// Wait blocks until the listing of a watched directory changes.
+func (w *Watcher) Wait() tea.Cmd {
+	return func() tea.Msg {
+		for {
+			select {
+			case event, ok := <-w.watcher.Events:
+				if !ok {
+					return nil
+				}
+
+				if event.Op&(fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+					continue
+				}
+
+				timeout := time.After(watchDebounce)
+				for {
+					select {
+					case _, ok := <-w.watcher.Events:
+						if !ok {
+							return nil
+						}
+					case <-timeout:
+						return watchMsg{path: filepath.Dir(event.Name)}
+					}
+				}
+
+			case err, ok := <-w.watcher.Errors:
+				if !ok {
+					return nil
+				}
+
+				return watchMsg{err: err}
+			}
+		}
+	}
+}
+
+func (w *Watcher) Close() error {
+	return w.watcher.Close()
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	watcher, err := NewWatcher().Get()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	if err := watcher.Watch(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "foo"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := watcher.Wait()(), (watchMsg{path: dir}); got != want {
+		t.Errorf("watcher.Wait() = %v, want %v", got, want)
+	}
+}